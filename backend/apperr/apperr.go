@@ -0,0 +1,101 @@
+// Package apperr: Error ของแอปพลิเคชันที่มีรหัสคงที่ (Code) สำหรับโปรแกรมฝั่ง Client
+// Handler และ Middleware คืน *Error แล้วให้ ErrorHandler กลางของ Fiber แปลงเป็นคำตอบแบบ RFC 7807
+package apperr
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error: ข้อผิดพลาดที่ส่งกลับให้ Client ได้
+// Status และ Code คงที่ตามชนิดของปัญหา ส่วน Cause คือ Error ต้นเหตุที่เก็บไว้เขียน Log เท่านั้น ไม่ส่งให้ Client
+type Error struct {
+	Status     int                    // HTTP Status
+	Code       string                 // รหัสคงที่ เช่น "book_not_found" (Client ใช้ตัดสินใจ ห้ามเปลี่ยนชื่อ)
+	Message    string                 // ข้อความสำรองเมื่อแคตตาล็อกไม่มีรหัสนี้ (เช่น Error ของ Fiber เอง)
+	Extensions map[string]interface{} // ข้อมูลเพิ่มเติมของปัญหา เช่น fields, lines, allowed
+	Cause      error
+}
+
+// registry: ทุกชนิดที่ประกาศด้วย New (ใช้ตรวจว่าแคตตาล็อกข้อความมีครบทุกรหัส)
+var registry []*Error
+
+// New: ประกาศชนิดของปัญหาใหม่ (ใช้สร้างตัวแปร Err... ใน codes.go เท่านั้น)
+func New(status int, code string) *Error {
+	e := &Error{Status: status, Code: code}
+	registry = append(registry, e)
+	return e
+}
+
+// Codes: รหัสของทุกชนิดที่ประกาศไว้
+func Codes() []string {
+	codes := make([]string, len(registry))
+	for i, e := range registry {
+		codes[i] = e.Code
+	}
+	return codes
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Code + ": " + e.Cause.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is: Error สองตัวถือว่าเป็นปัญหาเดียวกันถ้ารหัสตรงกัน (errors.Is(err, apperr.ErrBookNotFound) ใช้ได้แม้ผ่าน With/Wrap)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// With: คืนสำเนาที่เพิ่มข้อมูลเพิ่มเติม (ไม่แก้ตัวแปรที่ประกาศไว้ซึ่งใช้ร่วมกันทุก Request)
+func (e *Error) With(key string, value interface{}) *Error {
+	clone := *e
+	clone.Extensions = make(map[string]interface{}, len(e.Extensions)+1)
+	for k, v := range e.Extensions {
+		clone.Extensions[k] = v
+	}
+	clone.Extensions[key] = value
+	return &clone
+}
+
+// Wrap: คืนสำเนาที่แนบ Error ต้นเหตุไว้ (ErrorHandler จะเขียน Log ให้ถ้าเป็นปัญหาฝั่ง Server)
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.Cause = cause
+	return &clone
+}
+
+// From: แปลง Error ใดๆ เป็น *Error
+// *fiber.Error ของ Fiber เอง (เช่น ไม่พบ Route, Body ใหญ่เกิน) ได้รหัสตาม HTTP Status ส่วน Error อื่นที่ไม่รู้จักถือเป็น ErrInternal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		if fiberErr.Code == fiber.StatusNotFound {
+			return ErrNotFound // ไม่พบ Route
+		}
+		return &Error{Status: fiberErr.Code, Code: statusCode(fiberErr.Code), Message: fiberErr.Message}
+	}
+	return ErrInternal.Wrap(err)
+}
+
+// statusCode: รหัสจากชื่อ HTTP Status เช่น 405 -> "method_not_allowed"
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
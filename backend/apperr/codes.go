@@ -0,0 +1,83 @@
+package apperr
+
+import "github.com/gofiber/fiber/v2"
+
+// ชนิดของปัญหาทั้งหมดที่ API ส่งกลับ (Code คือสัญญากับ Client ห้ามเปลี่ยนชื่อ เพิ่มใหม่ได้)
+// ข้อความของแต่ละรหัสอยู่ในแคตตาล็อกของ i18n (Key "error.<code>") ทุกภาษา
+
+// ทั่วไป
+var (
+	ErrBadRequest   = New(fiber.StatusBadRequest, "bad_request")
+	ErrValidation   = New(fiber.StatusUnprocessableEntity, "validation_failed")
+	ErrInvalidID    = New(fiber.StatusBadRequest, "invalid_id")
+	ErrUnauthorized = New(fiber.StatusUnauthorized, "unauthorized")
+	ErrTokenRevoked = New(fiber.StatusUnauthorized, "token_revoked")
+	ErrForbidden    = New(fiber.StatusForbidden, "forbidden")
+	ErrNotFound     = New(fiber.StatusNotFound, "not_found")
+	ErrInternal     = New(fiber.StatusInternalServerError, "internal_error")
+)
+
+// บัญชีผู้ใช้และการเข้าสู่ระบบ
+var (
+	ErrEmailTaken               = New(fiber.StatusConflict, "email_taken")
+	ErrInvalidCredentials       = New(fiber.StatusUnauthorized, "invalid_credentials")
+	ErrInvalidRefreshToken      = New(fiber.StatusUnauthorized, "invalid_refresh_token")
+	ErrRefreshTokenReused       = New(fiber.StatusUnauthorized, "refresh_token_reused")
+	ErrInvalidResetToken        = New(fiber.StatusBadRequest, "invalid_reset_token")
+	ErrInvalidVerificationToken = New(fiber.StatusBadRequest, "invalid_verification_token")
+	ErrEmailNotVerified         = New(fiber.StatusForbidden, "email_not_verified")
+	ErrEmailAlreadyVerified     = New(fiber.StatusConflict, "email_already_verified")
+	ErrUserNotFound             = New(fiber.StatusNotFound, "user_not_found")
+	ErrMailDelivery             = New(fiber.StatusBadGateway, "mail_delivery_failed")
+	ErrAccountSuspended         = New(fiber.StatusForbidden, "account_suspended")
+)
+
+// การจัดการผู้ใช้โดยผู้ดูแล
+var (
+	ErrUnknownRole             = New(fiber.StatusBadRequest, "unknown_role")
+	ErrCannotModifySelf        = New(fiber.StatusConflict, "cannot_modify_self")
+	ErrAccountAlreadySuspended = New(fiber.StatusConflict, "account_already_suspended")
+	ErrAccountNotSuspended     = New(fiber.StatusConflict, "account_not_suspended")
+)
+
+// หนังสือและการค้นหา
+var (
+	ErrBookNotFound        = New(fiber.StatusNotFound, "book_not_found")
+	ErrInvalidSort         = New(fiber.StatusBadRequest, "invalid_sort")
+	ErrInvalidSortOrder    = New(fiber.StatusBadRequest, "invalid_sort_order")
+	ErrInvalidPriceFilter  = New(fiber.StatusBadRequest, "invalid_price_filter")
+	ErrInvalidCursor       = New(fiber.StatusBadRequest, "invalid_cursor")
+	ErrSearchQueryRequired = New(fiber.StatusBadRequest, "search_query_required")
+)
+
+// สต็อกและสมุดบัญชีสต็อก
+var (
+	ErrStockManagedByInventory = New(fiber.StatusConflict, "stock_managed_by_inventory")
+	ErrInvalidMovementQuantity = New(fiber.StatusBadRequest, "invalid_movement_quantity")
+)
+
+// ตะกร้าสินค้าและคำสั่งซื้อ
+var (
+	ErrCartItemNotFound  = New(fiber.StatusNotFound, "cart_item_not_found")
+	ErrCartEmpty         = New(fiber.StatusBadRequest, "cart_empty")
+	ErrInsufficientStock = New(fiber.StatusConflict, "insufficient_stock")
+	ErrOrderNotFound     = New(fiber.StatusNotFound, "order_not_found")
+	ErrOrderNotPending   = New(fiber.StatusConflict, "order_not_pending")
+	ErrIllegalTransition = New(fiber.StatusConflict, "illegal_transition")
+)
+
+// การชำระเงิน
+var (
+	ErrPaymentNotFound         = New(fiber.StatusNotFound, "payment_not_found")
+	ErrInvalidWebhookSignature = New(fiber.StatusUnauthorized, "invalid_webhook_signature")
+	ErrPaymentGateway          = New(fiber.StatusBadGateway, "payment_gateway_error")
+	ErrRefundFailed            = New(fiber.StatusBadGateway, "refund_failed")
+	ErrCaptureFailed           = New(fiber.StatusConflict, "capture_failed")
+	ErrSlipAlreadyUsed         = New(fiber.StatusConflict, "slip_already_used")
+	ErrPromptPayNotConfigured  = New(fiber.StatusInternalServerError, "promptpay_not_configured")
+)
+
+// การตั้งค่าระบบ
+var (
+	ErrSettingNotFound = New(fiber.StatusNotFound, "setting_not_found")
+)
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"my-fiber-app/database"
+	"my-fiber-app/handlers"
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+)
+
+// usage: วิธีใช้คำสั่งย่อยของไบนารี (ไม่มีคำสั่งย่อย = รันเซิร์ฟเวอร์)
+const usage = `usage:
+  server                      start the API server
+  server migrate up           apply all pending migrations
+  server migrate down [n]     revert the last n migrations (default 1)
+  server migrate status       list migrations and whether they are applied
+  server admin create --email <email> [--name <name>] [--password-stdin]
+                              create an admin account (email already verified)
+  server admin promote --email <email> [--role <role>]
+                              give an existing user a role (default admin)
+  server admin reset-password --email <email> [--password-stdin]
+                              set a new password and log the user out everywhere
+
+  Without --password-stdin a random password is generated and printed once.`
+
+// runCommand: เลือกคำสั่งย่อยตามอาร์กิวเมนต์
+func runCommand(args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(args[1:])
+	case "admin":
+		return runAdmin(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+// runMigrate: คำสั่ง migrate up|down|status
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate action\n%s", usage)
+	}
+
+	db, err := database.Open()
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := database.MigrateUp(db)
+		for _, m := range ran {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate action %q\n%s", args[0], usage)
+	}
+}
+
+// runAdmin: คำสั่ง admin create|promote|reset-password สำหรับตั้งต้นระบบใหม่โดยไม่ต้องเขียน SQL เอง
+// ใช้การตรวจข้อมูลและการ Hash รหัสผ่านชุดเดียวกับ POST /signup และบันทึก AuditLog (actor_id = 0)
+func runAdmin(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing admin action\n%s", usage)
+	}
+
+	fs := flag.NewFlagSet("admin "+args[0], flag.ContinueOnError)
+	email := fs.String("email", "", "email of the account")
+	name := fs.String("name", "Administrator", "display name (admin create)")
+	role := fs.String("role", models.RoleAdmin, "role to give (admin promote)")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("--email is required\n%s", usage)
+	}
+
+	ctx := context.Background()
+	store, err := openStore()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		password, generated, err := readPassword(*passwordStdin)
+		if err != nil {
+			return err
+		}
+		if err := createAdmin(ctx, store, *name, *email, password); err != nil {
+			return err
+		}
+		fmt.Printf("created admin %s\n", *email)
+		if generated {
+			fmt.Printf("password: %s\n(shown once, change it after logging in)\n", password)
+		}
+		return nil
+
+	case "promote":
+		from, err := promoteUser(ctx, store, *email, *role)
+		if err != nil {
+			return err
+		}
+		if from == *role {
+			fmt.Printf("%s already has role %s\n", *email, *role)
+		} else {
+			fmt.Printf("%s: %s -> %s\n", *email, from, *role)
+		}
+		return nil
+
+	case "reset-password":
+		password, generated, err := readPassword(*passwordStdin)
+		if err != nil {
+			return err
+		}
+		if err := resetPassword(ctx, store, *email, password); err != nil {
+			return err
+		}
+		fmt.Printf("password of %s changed, all sessions revoked\n", *email)
+		if generated {
+			fmt.Printf("password: %s\n(shown once)\n", password)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown admin action %q\n%s", args[0], usage)
+	}
+}
+
+// openStore: เปิดฐานข้อมูลแบบเดียวกับตอนเริ่มเซิร์ฟเวอร์ (รัน Migration ที่ค้างและสร้างบทบาทเริ่มต้น)
+// เพื่อให้สร้างผู้ดูแลคนแรกได้ก่อนเปิดเซิร์ฟเวอร์ครั้งแรก
+func openStore() (repository.Store, error) {
+	db, err := database.Open()
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	ran, err := database.MigrateUp(db)
+	for _, m := range ran {
+		fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := database.SeedRoles(db); err != nil {
+		return nil, fmt.Errorf("seed roles: %w", err)
+	}
+	return repository.NewGormStore(db), nil
+}
+
+// createAdmin: สร้างบัญชีบทบาท admin ที่ยืนยันอีเมลแล้ว (ผู้สร้างคือผู้ดูแลเซิร์ฟเวอร์เอง)
+func createAdmin(ctx context.Context, store repository.Store, name, email, password string) error {
+	input := &handlers.SignUpInput{Name: name, Email: email, Password: password}
+	if err := input.Validate(i18n.English); err != nil {
+		return err
+	}
+	user, err := input.User()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	user.Role = models.RoleAdmin
+	user.EmailVerifiedAt = &now
+
+	err = store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().Create(ctx, user); err != nil {
+			return err
+		}
+		return tx.AuditLogs().Create(ctx, &models.AuditLog{
+			ActorID:    models.AuditActorCLI,
+			Action:     models.AuditUserCreated,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+			ToValue:    user.Role,
+		})
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("%s is already registered (use admin promote)", email)
+	}
+	return err
+}
+
+// promoteUser: เปลี่ยนบทบาทของผู้ใช้ คืนบทบาทเดิม
+func promoteUser(ctx context.Context, store repository.Store, email, role string) (string, error) {
+	if _, err := store.Users().GetRole(ctx, role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", fmt.Errorf("unknown role %q", role)
+		}
+		return "", err
+	}
+	user, err := findUser(ctx, store, email)
+	if err != nil {
+		return "", err
+	}
+	if user.Role == role {
+		return user.Role, nil
+	}
+
+	err = store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().UpdateRole(ctx, user.ID, role); err != nil {
+			return err
+		}
+		return tx.AuditLogs().Create(ctx, &models.AuditLog{
+			ActorID:    models.AuditActorCLI,
+			Action:     models.AuditUserRoleChanged,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+			FromValue:  user.Role,
+			ToValue:    role,
+		})
+	})
+	return user.Role, err
+}
+
+// resetPassword: ตั้งรหัสผ่านใหม่ให้ผู้ใช้ (เช่น ผู้ดูแลที่ลืมรหัสผ่านบนระบบที่ยังไม่ได้ตั้งค่าอีเมล)
+func resetPassword(ctx context.Context, store repository.Store, email, password string) error {
+	input := &handlers.PasswordInput{Password: password}
+	if err := input.Validate(i18n.English); err != nil {
+		return err
+	}
+	user, err := findUser(ctx, store, email)
+	if err != nil {
+		return err
+	}
+	hash, err := input.Hash()
+	if err != nil {
+		return err
+	}
+
+	return store.Transaction(ctx, func(tx repository.Store) error {
+		if err := handlers.ReplacePassword(ctx, tx, user.ID, hash); err != nil {
+			return err
+		}
+		return tx.AuditLogs().Create(ctx, &models.AuditLog{
+			ActorID:    models.AuditActorCLI,
+			Action:     models.AuditUserPasswordReset,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+		})
+	})
+}
+
+// findUser: ผู้ใช้จากอีเมล พร้อมข้อความที่อ่านเข้าใจถ้าไม่พบ
+func findUser(ctx context.Context, store repository.Store, email string) (*models.User, error) {
+	user, err := store.Users().GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+	return user, err
+}
+
+// readPassword: อ่านรหัสผ่านจากบรรทัดแรกของ stdin หรือสุ่มใหม่ (คืน generated = true)
+// ไม่รับรหัสผ่านทางอาร์กิวเมนต์ เพราะจะค้างอยู่ใน Shell history และรายการ Process
+func readPassword(fromStdin bool) (password string, generated bool, err error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", false, fmt.Errorf("read password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), false, nil
+	}
+
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), true, nil
+}
@@ -1,54 +1,115 @@
 package database
 
 import (
-    "fmt"
-    "log"
-    "os"
+	"fmt"
+	"log"
+	"os"
+	"strings"
 
-    "gorm.io/driver/postgres"
-    "gorm.io/gorm"
-    "my-fiber-app/models"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// ชนิดฐานข้อมูลที่รองรับ (ตั้งค่าด้วย DB_DRIVER)
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// defaultSQLitePath: ไฟล์ฐานข้อมูล SQLite เมื่อไม่ได้ระบุ DB_NAME
+const defaultSQLitePath = "bookstore.db"
+
 // ตัวแปร Global เอาไว้ให้หน้านั้นเรียกใช้
 var DB *gorm.DB
 
-//function สำหรับเชื่อมต่อฐานข้อมูล
+// Open: เปิดการเชื่อมต่อฐานข้อมูลอย่างเดียว (ไม่ Migrate) ใช้กับคำสั่ง migrate
+// DB_DRIVER เลือกชนิดฐานข้อมูล: "postgres" (ค่าเริ่มต้น) หรือ "sqlite"
+func Open() (*gorm.DB, error) {
+	config := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+		// แปลง Error เฉพาะของฐานข้อมูล (เช่น Unique ซ้ำ) เป็น gorm.ErrDuplicatedKey
+		TranslateError: true,
+	}
+
+	switch driver := strings.ToLower(os.Getenv("DB_DRIVER")); driver {
+	case "", DriverPostgres:
+		return openPostgres(config)
+	case DriverSQLite:
+		return openSQLite(config)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q (use %q or %q)", driver, DriverPostgres, DriverSQLite)
+	}
+}
+
+// openPostgres: เชื่อมต่อ PostgreSQL จากค่า DB_HOST, DB_USER, DB_PASSWORD, DB_NAME, DB_PORT
+func openPostgres(config *gorm.Config) (*gorm.DB, error) {
+	//เก็บข้อมูลที่ใช้สำหรับเชื่อมต่อฐานไว้ที่ตัวแปร dsn
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Bangkok",
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_PORT"),
+	)
+
+	//ทำการสร้างอ๊อบเจคขึ้นมาเพื่อเก็บข้อมูลการเชื่อมต่อฐานข้อมูลและ error
+	return gorm.Open(postgres.Open(dsn), config)
+}
+
+// openSQLite: เปิดไฟล์ SQLite จาก DB_NAME (หรือ ":memory:" สำหรับฐานข้อมูลชั่วคราวในหน่วยความจำ)
+// ไม่ต้องมีเซิร์ฟเวอร์ฐานข้อมูล เหมาะกับการพัฒนาบนเครื่องและการทดสอบ
+func openSQLite(config *gorm.Config) (*gorm.DB, error) {
+	path := os.Getenv("DB_NAME")
+	if path == "" {
+		path = defaultSQLitePath
+	}
+	// เปิด Foreign Key (SQLite ปิดไว้โดยค่าเริ่มต้น) และรอแทนที่จะ Error ทันทีเมื่อไฟล์ถูกล็อก
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := gorm.Open(sqlite.Open(dsn), config)
+	if err != nil {
+		return nil, err
+	}
+
+	// ใช้ Connection เดียว: ":memory:" แต่ละ Connection คือฐานข้อมูลแยกกัน
+	// และ SQLite เขียนได้ทีละ Transaction อยู่แล้ว (จึงแทน SELECT ... FOR UPDATE ที่ SQLite ไม่มี)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// function สำหรับเชื่อมต่อฐานข้อมูล
 func ConnectDb() {
-    //เก็บข้อมูลที่ใช้สำหรับเชื่อมต่อฐานไว้ที่ตัวแปร dsn
-    dsn := fmt.Sprintf(
-        "host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Bangkok",
-        os.Getenv("DB_HOST"),
-        os.Getenv("DB_USER"),
-        os.Getenv("DB_PASSWORD"),
-        os.Getenv("DB_NAME"),
-        os.Getenv("DB_PORT"),
-    )
-
-    //ทำการสร้างอ๊อบเจคขึ้นมาเพื่อเก็บข้อมูลการเชื่อมต่อฐานข้อมูลและ error
-    db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Info),
-    })
-    //ถ้าเกิด error ให้ทำการแสดง log error แจ้ง user
-    if err != nil {
-        log.Fatal("Failed to connect to database. \n", err)
-    }
-
-    log.Println("✅ Database connected successfully")
-
-    // Auto Migrate ย้ายมาทำตรงนี้
-    log.Println("🚀 Running migrations...")
-    err = db.AutoMigrate(
-        &models.Book{}, 
-        &models.User{}, 
-        &models.CartItem{},
-    )
-    if err != nil {
-        log.Fatal("❌ Migration failed: ", err)
-    }
-    log.Println("✅ Migrations completed successfully")
-
-    // เก็บค่า connection ไว้ในตัวแปร Global
-    DB = db
-}
\ No newline at end of file
+	db, err := Open()
+	//ถ้าเกิด error ให้ทำการแสดง log error แจ้ง user
+	if err != nil {
+		log.Fatal("Failed to connect to database. \n", err)
+	}
+
+	log.Println("✅ Database connected successfully")
+
+	// รัน Migration ที่ยังค้างอยู่ (ถือ Advisory Lock ไว้ Instance อื่นที่เริ่มพร้อมกันจะรอจนเสร็จ)
+	log.Println("🚀 Running migrations...")
+	ran, err := MigrateUp(db)
+	if err != nil {
+		log.Fatal("❌ Migration failed: ", err)
+	}
+	for _, m := range ran {
+		log.Printf("   applied %04d_%s", m.Version, m.Name)
+	}
+	log.Println("✅ Migrations completed successfully")
+
+	// สร้างบทบาท (Role) และสิทธิ์ (Permission) เริ่มต้น
+	if err := seedRoles(db); err != nil {
+		log.Fatal("❌ Seeding roles failed: ", err)
+	}
+
+	// เก็บค่า connection ไว้ในตัวแปร Global
+	DB = db
+}
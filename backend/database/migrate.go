@@ -0,0 +1,263 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ไฟล์ Migration ทั้งหมดถูกฝังไว้ในไบนารี ตั้งชื่อรูปแบบ NNNN_ชื่อ.up.sql / NNNN_ชื่อ.down.sql
+// แยกโฟลเดอร์ตามชนิดฐานข้อมูล (migrations/postgres, migrations/sqlite) โดยใช้เลขเวอร์ชันเดียวกัน
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey: คีย์ของ Advisory Lock ที่ใช้กันไม่ให้หลาย Instance Migrate พร้อมกัน
+const migrationLockKey int64 = 7262030100
+
+// Migration: การเปลี่ยนแปลงโครงสร้างฐานข้อมูลหนึ่งเวอร์ชัน
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus: สถานะของ Migration แต่ละเวอร์ชัน (AppliedAt เป็น nil ถ้ายังไม่ได้รัน)
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// loadMigrations: อ่านไฟล์ Migration ที่ฝังไว้ของฐานข้อมูลชนิดนี้ เรียงตามเวอร์ชัน
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", dialect)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must look like NNNN_name.%s.sql", name, direction)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, found := byVersion[version]
+		if !found {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration %04d: conflicting names %q and %q", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s: missing up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// withMigrationLock: ถือ Advisory Lock บน Connection เดียวตลอดการทำงานของ fn
+// Advisory Lock ผูกกับ Session จึงต้องใช้ Connection เดียวกันทั้งล็อก ทำงาน และปลดล็อก
+// SQLite ไม่มี Advisory Lock แต่เปิดได้ทีละ Connection อยู่แล้ว (ดู Open) จึงข้ามขั้นตอนล็อก
+func withMigrationLock(db *gorm.DB, fn func(ctx context.Context, conn *sql.Conn) error) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	createTable := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+	if db.Dialector.Name() == DriverSQLite {
+		createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+	} else {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	}
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(ctx, conn)
+}
+
+// appliedVersions: เวอร์ชันที่รันไปแล้ว พร้อมเวลาที่รัน
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// runMigration: รัน SQL ของ Migration และบันทึก/ลบเวอร์ชันใน Transaction เดียวกัน
+func runMigration(ctx context.Context, conn *sql.Conn, m Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	body, record, args := m.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", []interface{}{m.Version, m.Name}
+	if !up {
+		body, record, args = m.Down, "DELETE FROM schema_migrations WHERE version = $1", []interface{}{m.Version}
+	}
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateUp: รัน Migration ที่ยังไม่ได้รันทั้งหมดตามลำดับ คืนรายการที่รันไป
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	err = withMigrationLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, done := applied[m.Version]; done {
+				continue
+			}
+			if err := runMigration(ctx, conn, m, true); err != nil {
+				return err
+			}
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// MigrateDown: ย้อน Migration ล่าสุดกลับไป steps เวอร์ชัน คืนรายการที่ย้อนไป
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	err = withMigrationLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if _, done := applied[m.Version]; !done {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %04d_%s: missing down file", m.Version, m.Name)
+			}
+			if err := runMigration(ctx, conn, m, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatuses: รายการ Migration ทั้งหมดพร้อมสถานะว่ารันแล้วหรือยัง
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withMigrationLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			status := MigrationStatus{Migration: m}
+			if at, done := applied[m.Version]; done {
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
@@ -0,0 +1,42 @@
+-- ตารางหลักของระบบ: หนังสือ ผู้ใช้ และตะกร้าสินค้า
+-- ใช้ IF NOT EXISTS เพื่อให้ฐานข้อมูลเดิมที่สร้างด้วย AutoMigrate ย้ายมาใช้ Migration ได้ทันที
+
+CREATE TABLE IF NOT EXISTS books (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    title       TEXT,
+    author      TEXT,
+    price       BIGINT,
+    image_url   TEXT,
+    stock       BIGINT DEFAULT 0,
+    description TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_books_title ON books (title);
+CREATE INDEX IF NOT EXISTS idx_books_price ON books (price);
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    email      TEXT NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password   TEXT NOT NULL,
+    name       TEXT,
+    role       TEXT DEFAULT 'user'
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    user_id    BIGINT NOT NULL,
+    book_id    BIGINT NOT NULL,
+    quantity   BIGINT DEFAULT 1,
+    CONSTRAINT fk_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_cart_items_deleted_at ON cart_items (deleted_at);
@@ -0,0 +1,28 @@
+-- บทบาทและสิทธิ์ (RBAC) ข้อมูลเริ่มต้นถูกสร้างโดย seedRoles ตอนเริ่มระบบ
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    name        TEXT NOT NULL CONSTRAINT uni_permissions_name UNIQUE,
+    description TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_permissions_deleted_at ON permissions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name       TEXT NOT NULL CONSTRAINT uni_roles_name UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       BIGINT NOT NULL,
+    permission_id BIGINT NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
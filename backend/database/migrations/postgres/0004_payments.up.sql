@@ -0,0 +1,30 @@
+-- การชำระเงินและ Webhook ที่ประมวลผลแล้ว (event_id ไม่ซ้ำ ใช้กัน Replay)
+
+CREATE TABLE IF NOT EXISTS payments (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    order_id   BIGINT NOT NULL,
+    provider   TEXT NOT NULL,
+    intent_id  TEXT NOT NULL,
+    amount     BIGINT NOT NULL,
+    currency   TEXT NOT NULL,
+    status     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_payments_deleted_at ON payments (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_intent_id ON payments (intent_id);
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    event_id   TEXT NOT NULL,
+    type       TEXT NOT NULL,
+    intent_id  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_payment_events_deleted_at ON payment_events (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_events_event_id ON payment_events (event_id);
+CREATE INDEX IF NOT EXISTS idx_payment_events_intent_id ON payment_events (intent_id);
@@ -0,0 +1,15 @@
+-- ค้นหาหนังสือ: คอลัมน์ tsvector แบบ Generated + GIN Index และ Trigram Index สำหรับคำสะกดผิด
+-- อักขระไทย (U+0E00–U+0E7F) ถูกแยกเป็นตัวละ Token ให้ตรงกับ search.SpaceThai
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', regexp_replace(coalesce(title, ''), '([฀-๿])', '\1 ', 'g')), 'A') ||
+        setweight(to_tsvector('simple', regexp_replace(coalesce(author, ''), '([฀-๿])', '\1 ', 'g')), 'B') ||
+        setweight(to_tsvector('simple', regexp_replace(coalesce(description, ''), '([฀-๿])', '\1 ', 'g')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_author_trgm ON books USING GIN (author gin_trgm_ops);
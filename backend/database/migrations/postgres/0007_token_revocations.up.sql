@@ -0,0 +1,20 @@
+-- การเพิกถอน Access Token: รายตัว (jti) และทั้งผู้ใช้ (ทุก Token ที่ออกก่อนเวลาที่กำหนด)
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    jti        TEXT NOT NULL,
+    user_id    BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_deleted_at ON revoked_tokens (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_revoked_tokens_jti ON revoked_tokens (jti);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_user_id ON revoked_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS session_revocations (
+    user_id        BIGINT PRIMARY KEY,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,11 @@
+-- ยืนยันอีเมล: เวลาที่ผู้ใช้ยืนยันอีเมล และตารางการตั้งค่าระบบที่ผู้ดูแลปรับได้
+-- บัญชีที่มีอยู่ก่อนแล้วถือว่ายืนยันแล้ว (สมัครก่อนมีระบบยืนยันอีเมล)
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+UPDATE users SET email_verified_at = COALESCE(created_at, NOW()) WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS settings (
+    key        TEXT PRIMARY KEY,
+    value      TEXT NOT NULL,
+    updated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+-- ภาษาที่ผู้ใช้เลือกสำหรับข้อความจาก API ("th", "en") ค่าว่าง = ตาม Header Accept-Language
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,20 @@
+-- ตะกร้าของผู้ที่ยังไม่ได้เข้าสู่ระบบ (รวมเข้าตะกร้าของผู้ใช้ตอนเข้าสู่ระบบหรือสมัครสมาชิก)
+
+CREATE TABLE IF NOT EXISTS guest_carts (
+    id         TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_guest_carts_updated_at ON guest_carts (updated_at);
+
+CREATE TABLE IF NOT EXISTS guest_cart_items (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    guest_cart_id TEXT NOT NULL,
+    book_id       BIGINT NOT NULL,
+    quantity      BIGINT NOT NULL,
+    CONSTRAINT fk_guest_carts_items FOREIGN KEY (guest_cart_id) REFERENCES guest_carts (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_guest_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guest_cart_items_cart_book ON guest_cart_items (guest_cart_id, book_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_cart_items_user_book;
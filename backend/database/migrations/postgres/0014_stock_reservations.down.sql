@@ -0,0 +1,6 @@
+-- การจองที่ยังค้างอยู่กลับไปเป็นสต็อกที่ถูกตัดแล้ว (แบบเดิมก่อนมีการจอง)
+UPDATE books SET stock = stock - (
+    SELECT SUM(r.quantity) FROM stock_reservations r WHERE r.book_id = books.id
+)
+WHERE id IN (SELECT book_id FROM stock_reservations);
+DROP TABLE IF EXISTS stock_reservations;
@@ -0,0 +1,30 @@
+-- การจองสต็อกของคำสั่งซื้อที่รอชำระเงิน (Checkout จองไว้แทนการตัดสต็อกทันที)
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    order_id   BIGINT NOT NULL,
+    book_id    BIGINT NOT NULL,
+    quantity   BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_stock_reservations_order FOREIGN KEY (order_id) REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_stock_reservations_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_order_id ON stock_reservations (order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_book_id ON stock_reservations (book_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expires_at ON stock_reservations (expires_at);
+
+-- คำสั่งซื้อที่รอชำระเงินอยู่ก่อนแล้วถูกตัดสต็อกไปตอน Checkout: คืนสต็อกแล้วเปลี่ยนเป็นการจอง (ให้เวลาชำระอีก 15 นาที)
+UPDATE books SET stock = stock + (
+    SELECT SUM(oi.quantity) FROM order_items oi JOIN orders o ON o.id = oi.order_id
+    WHERE oi.book_id = books.id AND o.status = 'pending_payment' AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+)
+WHERE id IN (
+    SELECT oi.book_id FROM order_items oi JOIN orders o ON o.id = oi.order_id
+    WHERE o.status = 'pending_payment' AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+);
+INSERT INTO stock_reservations (created_at, order_id, book_id, quantity, expires_at)
+SELECT NOW(), oi.order_id, oi.book_id, SUM(oi.quantity), NOW() + INTERVAL '15 minutes'
+FROM order_items oi JOIN orders o ON o.id = oi.order_id
+WHERE o.status = 'pending_payment' AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+GROUP BY oi.order_id, oi.book_id;
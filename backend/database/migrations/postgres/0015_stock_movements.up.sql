@@ -0,0 +1,22 @@
+-- สมุดบัญชีสต็อก (เพิ่มได้อย่างเดียว): ทุกการเปลี่ยน books.stock มีบันทึกว่าใคร ทำไม เท่าไร
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    book_id     BIGINT NOT NULL,
+    type        TEXT NOT NULL,
+    quantity    BIGINT NOT NULL,
+    stock_after BIGINT NOT NULL,
+    actor_id    BIGINT,
+    order_id    BIGINT,
+    reason      TEXT,
+    CONSTRAINT fk_stock_movements_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_book_id ON stock_movements (book_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_type ON stock_movements (type);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_order_id ON stock_movements (order_id);
+
+-- ยอดยกมา: สต็อกปัจจุบันของแต่ละเล่มเป็นรายการแรกในสมุด ผลรวมของสมุดจึงเท่ากับ books.stock ตั้งแต่ต้น
+INSERT INTO stock_movements (created_at, book_id, type, quantity, stock_after, reason)
+SELECT NOW(), id, 'adjustment', stock, stock, 'opening balance'
+FROM books WHERE stock <> 0;
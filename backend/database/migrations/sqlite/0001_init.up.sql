@@ -0,0 +1,41 @@
+-- ตารางหลักของระบบ: หนังสือ ผู้ใช้ และตะกร้าสินค้า (SQLite)
+
+CREATE TABLE IF NOT EXISTS books (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME,
+    title       TEXT,
+    author      TEXT,
+    price       INTEGER,
+    image_url   TEXT,
+    stock       INTEGER DEFAULT 0,
+    description TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_books_title ON books (title);
+CREATE INDEX IF NOT EXISTS idx_books_price ON books (price);
+
+CREATE TABLE IF NOT EXISTS users (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    email      TEXT NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password   TEXT NOT NULL,
+    name       TEXT,
+    role       TEXT DEFAULT 'user'
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    user_id    INTEGER NOT NULL,
+    book_id    INTEGER NOT NULL,
+    quantity   INTEGER DEFAULT 1,
+    CONSTRAINT fk_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_cart_items_deleted_at ON cart_items (deleted_at);
@@ -0,0 +1,28 @@
+-- บทบาทและสิทธิ์ (RBAC) ข้อมูลเริ่มต้นถูกสร้างโดย seedRoles ตอนเริ่มระบบ (SQLite)
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME,
+    name        TEXT NOT NULL CONSTRAINT uni_permissions_name UNIQUE,
+    description TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_permissions_deleted_at ON permissions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    name       TEXT NOT NULL CONSTRAINT uni_roles_name UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       INTEGER NOT NULL,
+    permission_id INTEGER NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
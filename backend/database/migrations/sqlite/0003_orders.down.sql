@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS order_transitions;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
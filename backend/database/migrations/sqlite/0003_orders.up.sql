@@ -0,0 +1,43 @@
+-- คำสั่งซื้อ รายการสินค้า และประวัติการเปลี่ยนสถานะ (SQLite)
+
+CREATE TABLE IF NOT EXISTS orders (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    user_id    INTEGER NOT NULL,
+    status     TEXT NOT NULL DEFAULT 'pending_payment',
+    total      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    order_id   INTEGER NOT NULL,
+    book_id    INTEGER NOT NULL,
+    title      TEXT NOT NULL,
+    price      INTEGER NOT NULL,
+    quantity   INTEGER NOT NULL,
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id) REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_order_items_deleted_at ON order_items (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+
+CREATE TABLE IF NOT EXISTS order_transitions (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME,
+    order_id    INTEGER NOT NULL,
+    from_status TEXT,
+    to_status   TEXT NOT NULL,
+    actor_id    INTEGER,
+    reason      TEXT,
+    CONSTRAINT fk_orders_transitions FOREIGN KEY (order_id) REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_order_transitions_deleted_at ON order_transitions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_order_transitions_order_id ON order_transitions (order_id);
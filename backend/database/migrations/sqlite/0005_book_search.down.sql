@@ -0,0 +1 @@
+-- ไม่มีอะไรต้องย้อน ไฟล์นี้มีไว้ให้เลขเวอร์ชันตรงกับ Migration ของ PostgreSQL
@@ -0,0 +1,3 @@
+-- ค้นหาหนังสือ (SQLite): ไม่มี tsvector และ pg_trgm จึงไม่มีโครงสร้างเพิ่ม
+-- Repository ค้นด้วย LIKE แล้วคำนวณคะแนนและความคล้าย (Trigram) ใน Go แทน
+-- ไฟล์นี้มีไว้ให้เลขเวอร์ชันตรงกับ Migration ของ PostgreSQL
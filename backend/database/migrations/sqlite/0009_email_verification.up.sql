@@ -0,0 +1,11 @@
+-- ยืนยันอีเมล: เวลาที่ผู้ใช้ยืนยันอีเมล และตารางการตั้งค่าระบบที่ผู้ดูแลปรับได้ (SQLite)
+-- บัญชีที่มีอยู่ก่อนแล้วถือว่ายืนยันแล้ว (สมัครก่อนมีระบบยืนยันอีเมล)
+
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME;
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL AND created_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS settings (
+    key        TEXT PRIMARY KEY,
+    value      TEXT NOT NULL,
+    updated_at DATETIME
+);
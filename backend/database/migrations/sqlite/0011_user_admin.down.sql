@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_logs;
+ALTER TABLE users DROP COLUMN suspend_reason;
+ALTER TABLE users DROP COLUMN suspended_at;
@@ -0,0 +1,20 @@
+-- การจัดการผู้ใช้โดยผู้ดูแล: ระงับบัญชี และบันทึกการกระทำของผู้ดูแล (เพิ่มได้อย่างเดียว) (SQLite)
+
+ALTER TABLE users ADD COLUMN suspended_at DATETIME;
+ALTER TABLE users ADD COLUMN suspend_reason TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    actor_id    INTEGER NOT NULL,
+    action      TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id   INTEGER NOT NULL,
+    from_value  TEXT,
+    to_value    TEXT,
+    reason      TEXT,
+    request_id  TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs (target_type, target_id);
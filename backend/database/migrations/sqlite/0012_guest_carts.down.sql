@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS guest_cart_items;
+DROP TABLE IF EXISTS guest_carts;
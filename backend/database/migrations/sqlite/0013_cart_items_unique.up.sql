@@ -0,0 +1,15 @@
+-- ตะกร้าสินค้า: หนังสือหนึ่งเล่มมีได้รายการเดียวต่อผู้ใช้ (ให้ AddToCart บวกจำนวนด้วย Upsert ได้) (SQLite)
+-- ต่อจากนี้รายการในตะกร้าถูกลบจริง ไม่ใช่ Soft Delete
+
+-- 1. ลบรายการที่ถูก Soft Delete ไปแล้วทิ้งจริง
+DELETE FROM cart_items WHERE deleted_at IS NOT NULL;
+
+-- 2. รวมรายการซ้ำที่เกิดจาก Request พร้อมกัน: บวกจำนวนเข้ารายการแรก แล้วลบรายการที่เหลือ
+UPDATE cart_items SET quantity = (
+    SELECT SUM(d.quantity) FROM cart_items d
+    WHERE d.user_id = cart_items.user_id AND d.book_id = cart_items.book_id
+)
+WHERE id IN (SELECT MIN(id) FROM cart_items GROUP BY user_id, book_id HAVING COUNT(*) > 1);
+DELETE FROM cart_items WHERE id NOT IN (SELECT MIN(id) FROM cart_items GROUP BY user_id, book_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_user_book ON cart_items (user_id, book_id);
@@ -0,0 +1,22 @@
+-- สมุดบัญชีสต็อก (เพิ่มได้อย่างเดียว): ทุกการเปลี่ยน books.stock มีบันทึกว่าใคร ทำไม เท่าไร (SQLite)
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    book_id     INTEGER NOT NULL,
+    type        TEXT NOT NULL,
+    quantity    INTEGER NOT NULL,
+    stock_after INTEGER NOT NULL,
+    actor_id    INTEGER,
+    order_id    INTEGER,
+    reason      TEXT,
+    CONSTRAINT fk_stock_movements_book FOREIGN KEY (book_id) REFERENCES books (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_book_id ON stock_movements (book_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_type ON stock_movements (type);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_order_id ON stock_movements (order_id);
+
+-- ยอดยกมา: สต็อกปัจจุบันของแต่ละเล่มเป็นรายการแรกในสมุด ผลรวมของสมุดจึงเท่ากับ books.stock ตั้งแต่ต้น
+INSERT INTO stock_movements (created_at, book_id, type, quantity, stock_after, reason)
+SELECT datetime('now'), id, 'adjustment', stock, stock, 'opening balance'
+FROM books WHERE stock <> 0;
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedRoles: สร้างบทบาทและสิทธิ์เริ่มต้น (เรียกซ้ำได้ ไม่สร้างข้อมูลซ้ำ)
+// ทุก INSERT ใช้ ON CONFLICT DO NOTHING แล้วค่อยอ่านแถวกลับมา
+// Instance ที่เริ่มพร้อมกันจึงไม่ชน Unique Constraint ของกันและกัน
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		perms := make(map[string]models.Permission, len(models.DefaultPermissions))
+		for name, desc := range models.DefaultPermissions {
+			p := models.Permission{Name: name, Description: desc}
+			if err := insertIgnore(tx, "name", &p); err != nil {
+				return err
+			}
+			if err := tx.Where("name = ?", name).First(&p).Error; err != nil {
+				return err
+			}
+			perms[name] = p
+		}
+
+		for name, permNames := range models.DefaultRoles {
+			role := models.Role{Name: name}
+			if err := insertIgnore(tx, "name", &role); err != nil {
+				return err
+			}
+			if err := tx.Where("name = ?", name).First(&role).Error; err != nil {
+				return err
+			}
+			if len(permNames) == 0 {
+				continue
+			}
+			// ให้สิทธิ์ที่ยังไม่มี แถวที่มีอยู่แล้วในตาราง role_permissions ถูกข้ามไป
+			grants := make([]map[string]interface{}, 0, len(permNames))
+			for _, pn := range permNames {
+				grants = append(grants, map[string]interface{}{"role_id": role.ID, "permission_id": perms[pn].ID})
+			}
+			if err := tx.Table("role_permissions").Clauses(clause.OnConflict{DoNothing: true}).Create(grants).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertIgnore: INSERT ... ON CONFLICT (column) DO NOTHING (ไม่ Error ถ้ามีแถวนั้นอยู่แล้ว)
+func insertIgnore(tx *gorm.DB, column string, value interface{}) error {
+	return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: column}}, DoNothing: true}).Create(value).Error
+}
+
+// SeedRoles: สร้างบทบาทและสิทธิ์เริ่มต้น สำหรับคำสั่งย่อยที่เปิดฐานข้อมูลเองโดยไม่ผ่าน ConnectDb
+func SeedRoles(db *gorm.DB) error {
+	return seedRoles(db)
+}
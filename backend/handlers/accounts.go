@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordCost: ค่า Cost ของ bcrypt สำหรับรหัสผ่านทุกที่ในระบบ
+const passwordCost = 14
+
+// SignUpInput: ข้อมูลบัญชีใหม่ ใช้กฎเดียวกันทั้ง POST /signup และคำสั่ง admin create
+// ใช้ Struct แยกจาก models.User เพราะ User.Password มี json:"-" (อ่านจาก Body ไม่ได้)
+// และไม่ให้ผู้สมัครกำหนด Role หรือ Field อื่นเองได้
+type SignUpInput struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"` // bcrypt ใช้ได้ไม่เกิน 72 ไบต์
+	Language string `json:"language" validate:"omitempty,oneof=th en"` // ไม่ระบุ = ตาม Accept-Language
+}
+
+// Validate: ตรวจข้อมูลตาม Tag validate (ข้อความของ Field ที่ไม่ผ่านเป็นภาษา lang)
+func (in *SignUpInput) Validate(lang i18n.Language) error {
+	return validateStruct(in, lang)
+}
+
+// User: สร้าง User ที่ Hash รหัสผ่านแล้ว (ยังไม่บันทึก และบทบาทเป็นค่าเริ่มต้น)
+func (in *SignUpInput) User() (*models.User, error) {
+	hash, err := hashPassword(in.Password)
+	if err != nil {
+		return nil, err
+	}
+	return &models.User{
+		Name:     in.Name,
+		Email:    in.Email,
+		Password: hash,
+		Language: in.Language,
+	}, nil
+}
+
+// PasswordInput: รหัสผ่านใหม่ของบัญชีที่มีอยู่แล้ว (กฎเดียวกับตอนสมัคร)
+type PasswordInput struct {
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// Validate: ตรวจรหัสผ่านตาม Tag validate
+func (in *PasswordInput) Validate(lang i18n.Language) error {
+	return validateStruct(in, lang)
+}
+
+// Hash: Hash รหัสผ่านด้วย bcrypt
+func (in *PasswordInput) Hash() (string, error) {
+	return hashPassword(in.Password)
+}
+
+// ReplacePassword: เปลี่ยนรหัสผ่าน (Hash แล้ว) ยกเลิกลิงก์รีเซ็ตที่ยังไม่ใช้ และออกจากระบบทุกเครื่อง
+// เผื่อรหัสผ่านเดิมรั่วไหล ทั้งหมดอยู่ใน Transaction เดียว
+func ReplacePassword(ctx context.Context, store repository.Store, userID uint, hash string) error {
+	return store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().UpdatePassword(ctx, userID, hash); err != nil {
+			return err
+		}
+		if err := tx.PasswordResets().InvalidateUser(ctx, userID, time.Now()); err != nil {
+			return err
+		}
+		return revokeAllSessions(ctx, tx, userID)
+	})
+}
+
+// hashPassword: Hash รหัสผ่านด้วย bcrypt (ใช้เวลานาน ไม่ควรเรียกระหว่างถือล็อกของฐานข้อมูล)
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/mail"
+	"my-fiber-app/middleware"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+
+	jwtware "github.com/gofiber/contrib/jwt"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testJWTSecret: กุญแจเซ็น JWT ของการทดสอบ (ส่งเข้า Handler โดยตรง ไม่ได้ตั้ง JWT_SECRET)
+const testJWTSecret = "test-secret"
+
+// discardMailer: Mailer ที่ไม่ส่งอะไรเลย
+type discardMailer struct{}
+
+func (discardMailer) Send(context.Context, mail.Message) error { return nil }
+
+var _ mail.Mailer = discardMailer{}
+
+// testEnv: แอป Fiber ที่ต่อ Handler เข้ากับ MemoryStore และ Gateway จำลอง (Route เดียวกับ main.go)
+type testEnv struct {
+	t       *testing.T
+	store   *repository.MemoryStore
+	gateway *payments.Fake
+	app     *fiber.App
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	return newTestEnvWith(t, nil)
+}
+
+// newTestEnvWith: เหมือน newTestEnv แต่ Handler ใช้ที่เก็บข้อมูลที่ wrap ห่อ MemoryStore ไว้ (nil = ใช้ตรงๆ)
+// ใช้จำลองเหตุการณ์ที่เกิดซ้อนระหว่าง Request เช่น ตัวกวาดยกเลิกคำสั่งซื้อก่อนล็อกแถว
+func newTestEnvWith(t *testing.T, wrap func(*repository.MemoryStore) repository.Store) *testEnv {
+	t.Helper()
+	memory := repository.NewMemoryStore()
+	var store repository.Store = memory
+	if wrap != nil {
+		store = wrap(memory)
+	}
+	gateway := payments.NewFake("whsec_test")
+	auth := NewAuthHandler(store, discardMailer{}, "http://frontend.test", testJWTSecret)
+	books := NewBookHandler(store)
+	carts := NewCartHandler(store.Carts(), store.Books(), store.Reservations())
+	guestCarts := NewGuestCartHandler(store, testJWTSecret)
+	orders := NewOrderHandler(store, gateway, DefaultReservationTTL)
+	pay := NewPaymentHandler(store, gateway, "")
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.Language())
+	app.Get("/books/:id", books.GetBook)
+	app.Post("/signup", auth.SignUp)
+	app.Post("/login", auth.Login)
+	app.Get("/guest-cart", guestCarts.GetCart)
+	app.Post("/guest-cart", guestCarts.AddToCart)
+	app.Put("/guest-cart/:id", guestCarts.UpdateCartItem)
+	app.Delete("/guest-cart/:id", guestCarts.DeleteCartItem)
+	app.Post("/payments/webhook", pay.PaymentWebhook)
+
+	jwtMiddleware := jwtware.New(jwtware.Config{
+		SigningKey: jwtware.SigningKey{Key: []byte(testJWTSecret)},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return apperr.ErrUnauthorized.Wrap(err)
+		},
+		SuccessHandler: middleware.RejectRevoked(store.Revocations()),
+	})
+	api := app.Group("/api", jwtMiddleware, middleware.ActiveUser(store.Users()))
+	api.Post("/logout", auth.Logout)
+	api.Post("/cart", carts.AddToCart)
+	api.Get("/cart", carts.GetCart)
+	api.Put("/cart/:id", carts.UpdateCartItem)
+	api.Delete("/cart/:id", carts.DeleteCartItem)
+	api.Post("/checkout", orders.Checkout)
+	api.Get("/orders/:id", orders.GetOrder)
+	api.Post("/orders/:id/pay", pay.PayOrder)
+
+	// สิทธิ์ผู้ดูแลทดสอบแยกใน middleware ที่นี่ตรวจแค่ Token
+	admin := app.Group("/admin", jwtMiddleware, middleware.ActiveUser(store.Users()))
+	admin.Post("/book", books.CreateBook)
+	admin.Put("/book/:id", books.UpdateBook)
+	admin.Delete("/book/:id", books.DeleteBook)
+	admin.Post("/orders/:id/promptpay/confirm", pay.ConfirmPromptPayPayment)
+
+	return &testEnv{t: t, store: memory, gateway: gateway, app: app}
+}
+
+// request: ส่ง Request เข้าแอป (body เป็น nil ได้) แล้วคืน Status และ Body ที่แปลงจาก JSON แล้ว
+func (e *testEnv) request(method, path string, body interface{}, headers map[string]string) (int, map[string]interface{}) {
+	e.t.Helper()
+	resp := e.send(method, path, body, headers)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	out := map[string]interface{}{}
+	if len(raw) > 0 && raw[0] == '{' {
+		if err := json.Unmarshal(raw, &out); err != nil {
+			e.t.Fatalf("%s %s: invalid JSON %q: %v", method, path, raw, err)
+		}
+	}
+	return resp.StatusCode, out
+}
+
+// send: ส่ง Request เข้าแอปแล้วคืน Response ดิบ (ผู้เรียกต้องปิด Body เอง)
+func (e *testEnv) send(method, path string, body interface{}, headers map[string]string) *http.Response {
+	e.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			e.t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.app.Test(req, -1)
+	if err != nil {
+		e.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	return resp
+}
+
+// authed: Header Authorization ของ Token ที่ให้มา
+func authed(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+// createUser: สร้างผู้ใช้ตรงในที่เก็บข้อมูล (Hash แบบถูกเพื่อให้ทดสอบเร็ว) แล้วคืน Access Token
+func (e *testEnv) createUser(email string) (*models.User, string) {
+	e.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password1"), bcrypt.MinCost)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	user := &models.User{Email: email, Name: "Test", Password: string(hash), Role: models.RoleUser}
+	if err := e.store.Users().Create(context.Background(), user); err != nil {
+		e.t.Fatal(err)
+	}
+	token, err := signAccessToken([]byte(testJWTSecret), user, "test-session")
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return user, token
+}
+
+// createBook: สร้างหนังสือพร้อมรับของเข้าตามสต็อกที่ให้มา (ผ่านสมุดบัญชีสต็อกเหมือน CreateBook)
+func (e *testEnv) createBook(title string, price, stock int) models.Book {
+	e.t.Helper()
+	ctx := context.Background()
+	book := models.Book{Title: title, Price: price}
+	err := e.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Books().Create(ctx, &book); err != nil {
+			return err
+		}
+		return recordStockMovement(ctx, tx, &models.StockMovement{
+			BookID: book.ID, Type: models.MovementRestock, Quantity: stock, Reason: "test stock",
+		})
+	})
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	book.Stock = stock
+	return book
+}
+
+// stock: สต็อกปัจจุบันของหนังสือ
+func (e *testEnv) stock(bookID uint) int {
+	e.t.Helper()
+	book, err := e.store.Books().Get(context.Background(), bookID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return book.Stock
+}
+
+// orderStatus: สถานะปัจจุบันของคำสั่งซื้อ
+func (e *testEnv) orderStatus(orderID uint) string {
+	e.t.Helper()
+	order, err := e.store.Orders().Get(context.Background(), orderID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return order.Status
+}
+
+// checkout: ใส่หนังสือลงตะกร้าแล้วสั่งซื้อ คืน ID ของคำสั่งซื้อ
+func (e *testEnv) checkout(token string, bookID uint, quantity int) uint {
+	e.t.Helper()
+	if status, body := e.request("POST", "/api/cart", fiber.Map{"book_id": bookID, "quantity": quantity}, authed(token)); status != http.StatusOK {
+		e.t.Fatalf("add to cart: %d %v", status, body)
+	}
+	status, body := e.request("POST", "/api/checkout", nil, authed(token))
+	if status != http.StatusCreated {
+		e.t.Fatalf("checkout: %d %v", status, body)
+	}
+	return uint(body["ID"].(float64))
+}
+
+// wantStatus: ตรวจ Status ของคำตอบ พร้อมแสดง Body เมื่อไม่ตรง
+func wantStatus(t *testing.T, what string, got, want int, body map[string]interface{}) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s: status %d, want %d (body %v)", what, got, want, body)
+	}
+}
+
+// wantCode: ตรวจรหัส Error (problem+json) ของคำตอบ
+func wantCode(t *testing.T, what string, body map[string]interface{}, code string) {
+	t.Helper()
+	if body["code"] != code {
+		t.Fatalf("%s: code %v, want %q (body %v)", what, body["code"], code, body)
+	}
+}
@@ -1,90 +1,294 @@
 package handlers
 
 import (
-	"os"
+	"context"
+	"errors"
+	"log"
 	"time"
 
-	"my-fiber-app/database"
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/mail"
 	"my-fiber-app/models"
+	"my-fiber-app/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// AuthHandler: จัดการการสมัครสมาชิก ยืนยันอีเมล เข้าสู่ระบบ และการต่ออายุ Token
+type AuthHandler struct {
+	store     repository.Store
+	mailer    mail.Mailer
+	verifyURL string // หน้าเว็บที่รับ ?token= แล้วเรียก POST /email/verify
+	secret    []byte // JWT Secret: เซ็น Access Token และเป็นต้นทางของกุญแจลิงก์ยืนยันอีเมลและตะกร้า Guest
+}
+
+// NewAuthHandler: สร้าง AuthHandler (frontendURL ใช้สร้างลิงก์ยืนยันอีเมล jwtSecret ต้องตรงกับที่ JWT Middleware ใช้ตรวจ)
+func NewAuthHandler(store repository.Store, mailer mail.Mailer, frontendURL, jwtSecret string) *AuthHandler {
+	return &AuthHandler{store: store, mailer: mailer, verifyURL: frontendURL + "/verify-email", secret: []byte(jwtSecret)}
+}
+
 // SignUp: ฟังก์ชันสำหรับลงทะเบียนผู้ใช้ใหม่
-func SignUp(c *fiber.Ctx) error {
+func (h *AuthHandler) SignUp(c *fiber.Ctx) error {
 	// 1. รับข้อมูลจาก Request Body และตรวจสอบความถูกต้อง
-	user := new(models.User)
-	if err := c.BodyParser(user); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
+	input := new(SignUpInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
 	}
 
 	// 2. เข้ารหัสรหัสผ่าน (Hashing) เพื่อความปลอดภัย
 	// ใช้ bcrypt ในการแปลงรหัสผ่านจริงให้เป็นรหัสที่เดาไม่ได้
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), 14)
+	user, err := input.User()
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถจัดการรหัสผ่านได้"})
+		return apperr.ErrInternal.Wrap(err)
 	}
-	user.Password = string(hashedPassword)
 
 	// 3. บันทึกข้อมูลผู้ใช้ลงในฐานข้อมูล
-	if err := database.DB.Create(&user).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถสร้างผู้ใช้ได้ (อีเมลนี้อาจมีในระบบแล้ว)"})
+	if err := h.store.Users().Create(c.UserContext(), user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return apperr.ErrEmailTaken
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 4. ย้ายสินค้าที่เลือกไว้ก่อนสมัคร (ตะกร้า Guest) เข้าตะกร้าของบัญชีใหม่
+	// รวมไม่สำเร็จก็ยังสมัครสำเร็จ ตะกร้า Guest ยังอยู่ให้รวมตอนเข้าสู่ระบบครั้งถัดไป
+	merged, err := mergeGuestCart(c, h.store, h.secret, user.ID)
+	if err != nil {
+		log.Printf("guest cart: รวมตะกร้าของผู้ใช้ %d ไม่สำเร็จ: %v", user.ID, err)
 	}
 
-	// 4. ตอบกลับผลการสมัคร (ไม่ส่งรหัสผ่านกลับไป)
+	// 5. ส่งลิงก์ยืนยันอีเมลเบื้องหลัง (ส่งไม่สำเร็จก็ขอใหม่ได้ทาง /api/email/verify/resend)
+	i18n.SetPreferred(c, user.Language)
+	lang := i18n.From(c)
+	go func(user models.User) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := h.sendVerificationEmail(ctx, &user, lang); err != nil {
+			log.Printf("email verification: ส่งอีเมลไม่สำเร็จ: %v", err)
+		}
+	}(*user)
+
+	// 6. ตอบกลับผลการสมัคร (ไม่ส่งรหัสผ่านกลับไป)
 	return c.JSON(fiber.Map{
-		"message": "สมัครสมาชิกสำเร็จ",
-		"email":   user.Email,
-		"name":    user.Name,
+		"message":        i18n.T(lang, "message.signup_success"),
+		"email":          user.Email,
+		"name":           user.Name,
+		"language":       user.Language,
+		"email_verified": false,
+		"cart_merged":    merged,
 	})
 }
 
 // Login: ฟังก์ชันสำหรับเข้าสู่ระบบ
-func Login(c *fiber.Ctx) error {
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	// 1. รับข้อมูล Login (Email & Password)
 	type LoginInput struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
 	}
 
 	input := new(LoginInput)
-	if err := c.BodyParser(input); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
 	}
 
 	// 2. ค้นหาผู้ใช้จาก Email ในฐานข้อมูล
-	var user models.User
-	if err := database.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	user, err := h.store.Users().GetByEmail(c.UserContext(), input.Email)
+	if err != nil {
 		// แจ้งเตือนแบบกลางๆ เพื่อความปลอดภัย
-		return c.Status(401).JSON(fiber.Map{"error": "อีเมลหรือรหัสผ่านไม่ถูกต้อง"})
+		return apperr.ErrInvalidCredentials
 	}
 
 	// 3. ตรวจสอบรหัสผ่านที่กรอกมากับรหัสผ่านหน้าตาประหลาดในฐานข้อมูล
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": "อีเมลหรือรหัสผ่านไม่ถูกต้อง"})
+		return apperr.ErrInvalidCredentials
+	}
+
+	// บัญชีที่ผู้ดูแลระงับไว้เข้าสู่ระบบไม่ได้ (ตรวจหลังรหัสผ่าน เพื่อไม่บอกสถานะบัญชีกับคนที่ไม่รู้รหัสผ่าน)
+	if user.Suspended() {
+		return apperr.ErrAccountSuspended
+	}
+
+	// 4. เริ่ม Session ใหม่: ออก Refresh Token ใน Family ใหม่ (หนึ่ง Family ต่อการเข้าสู่ระบบหนึ่งครั้ง)
+	familyID, err := randomToken()
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	refresh, err := issueRefreshToken(c.UserContext(), h.store.Tokens(), user.ID, familyID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
-	// 4. เมื่อเข้าสู่ระบบสำเร็จ จะทำการสร้าง JWT Token (บัตรผ่านดิจิทัล)
-	claims := jwt.MapClaims{
-		"user_id": user.ID,                               // ระบุ ID ของผู้ใช้
-		"email":   user.Email,                            // ระบุ Email
-		"exp":     time.Now().Add(time.Hour * 72).Unix(), // กำหนดอายุการใช้งาน 3 วัน
+	// 5. สร้าง JWT Token (บัตรผ่านดิจิทัล) อายุสั้น ผูกกับ Session นี้
+	t, err := signAccessToken(h.secret, user, familyID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
-	// สร้าง Object สำหรับ Token และเซ็นชื่อยืนยันด้วย Secret Key จาก .env
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	t, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	// 6. รวมตะกร้า Guest (ถ้ามี) เข้าตะกร้าของผู้ใช้ รวมไม่สำเร็จก็ยังเข้าสู่ระบบได้
+	merged, err := mergeGuestCart(c, h.store, h.secret, user.ID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถสร้างบัตรผ่านได้"})
+		log.Printf("guest cart: รวมตะกร้าของผู้ใช้ %d ไม่สำเร็จ: %v", user.ID, err)
+	}
+
+	// 7. ส่ง Token และข้อมูลเบื้องต้นกลับไปให้ผู้ใช้เก็บไว้ใช้งาน (ตอบเป็นภาษาที่ผู้ใช้ตั้งไว้ ถ้ามี)
+	i18n.SetPreferred(c, user.Language)
+	return c.JSON(fiber.Map{
+		"message":        i18n.T(i18n.From(c), "message.login_success"),
+		"token":          t,
+		"refresh_token":  refresh,
+		"expires_in":     int(accessTokenTTL.Seconds()),
+		"role":           user.Role,
+		"name":           user.Name,
+		"language":       user.Language,
+		"email_verified": user.EmailVerified(),
+		"cart_merged":    merged,
+	})
+}
+
+// RefreshToken: แลก Refresh Token เป็น Access Token และ Refresh Token ใหม่ (Rotation)
+// Refresh Token ใช้ได้ครั้งเดียว ถ้าถูกใช้ซ้ำ ทุก Token ใน Family เดียวกันจะถูกเพิกถอนทันที
+func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	type RefreshInput struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+	input := new(RefreshInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	var user *models.User
+	var refresh, sessionID string
+	reused := false
+	err := h.store.Transaction(ctx, func(tx repository.Store) error {
+		// 1. หา Token จากค่า Hash พร้อมล็อก เพื่อไม่ให้คำขอสองรายการแลก Token เดียวกันได้พร้อมกัน
+		current, err := tx.Tokens().GetByHashForUpdate(ctx, hashToken(input.RefreshToken))
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		switch {
+		case current.RevokedAt != nil, now.After(current.ExpiresAt):
+			return errInvalidRefreshToken
+		case current.UsedAt != nil:
+			// 2. ถูกใช้ไปแล้ว แปลว่ามีคนอื่นถือ Token นี้อยู่ด้วย เพิกถอนทั้ง Family
+			// (คืน nil เพื่อให้การเพิกถอนถูกบันทึก ไม่ถูกยกเลิกไปกับ Transaction)
+			reused = true
+			return tx.Tokens().RevokeFamily(ctx, current.FamilyID, now)
+		}
+
+		// 3. ใช้ Token นี้ แล้วออก Token ใหม่ใน Family เดิม
+		if err := tx.Tokens().MarkUsed(ctx, current.ID, now); err != nil {
+			return err
+		}
+		user, err = tx.Users().Get(ctx, current.UserID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if user.Suspended() {
+			return apperr.ErrAccountSuspended
+		}
+		sessionID = current.FamilyID
+		refresh, err = issueRefreshToken(ctx, tx.Tokens(), user.ID, sessionID)
+		return err
+	})
+
+	switch {
+	case reused:
+		return apperr.ErrRefreshTokenReused
+	case errors.Is(err, errInvalidRefreshToken):
+		return apperr.ErrInvalidRefreshToken
+	case errors.Is(err, apperr.ErrAccountSuspended):
+		return apperr.ErrAccountSuspended
+	case err != nil:
+		return apperr.ErrInternal.Wrap(err)
 	}
 
-	// 5. ส่ง Token และข้อมูลเบื้องต้นกลับไปให้ผู้ใช้เก็บไว้ใช้งาน
+	t, err := signAccessToken(h.secret, user, sessionID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
 	return c.JSON(fiber.Map{
-		"message": "เข้าสู่ระบบสำเร็จ",
-		"token":   t,
-		"role":    user.Role,
-		"name":    user.Name,
+		"token":         t,
+		"refresh_token": refresh,
+		"expires_in":    int(accessTokenTTL.Seconds()),
 	})
 }
+
+// Logout: ออกจากระบบเครื่องนี้ เพิกถอน Access Token ที่ใช้เรียก และ Refresh Token ของ Session เดียวกัน
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	claims := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	jti, _ := claims["jti"].(string)
+	sessionID, _ := claims["sid"].(string)
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return apperr.ErrUnauthorized
+	}
+
+	now := time.Now()
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		// จำ jti ไว้จนกว่า Token จะหมดอายุเอง
+		if err := tx.Revocations().RevokeToken(ctx, &models.RevokedToken{
+			JTI:       jti,
+			UserID:    getUserID(c),
+			ExpiresAt: exp.Time,
+		}); err != nil {
+			return err
+		}
+		if sessionID != "" {
+			if err := tx.Tokens().RevokeFamily(ctx, sessionID, now); err != nil {
+				return err
+			}
+		}
+		// ถือโอกาสล้างรายการที่หมดอายุแล้ว ไม่ให้ Denylist โตไม่หยุด
+		return tx.Revocations().PurgeExpired(ctx, now)
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.logout_success")})
+}
+
+// LogoutAll: ออกจากระบบทุกเครื่องของผู้ใช้ที่เรียก (รวมเครื่องนี้ด้วย)
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	if err := revokeAllSessions(c.UserContext(), h.store, getUserID(c)); err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.logout_all_success")})
+}
+
+// UpdateLanguage: บันทึกภาษาที่ผู้ใช้ต้องการให้ API ตอบ ("th", "en" หรือค่าว่างเพื่อกลับไปใช้ Accept-Language)
+func (h *AuthHandler) UpdateLanguage(c *fiber.Ctx) error {
+	type LanguageInput struct {
+		Language string `json:"language" validate:"omitempty,oneof=th en"`
+	}
+	input := new(LanguageInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	if err := h.store.Users().SetLanguage(c.UserContext(), getUserID(c), input.Language); err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// ตอบเป็นภาษาใหม่ทันที (ค่าว่าง = กลับไปใช้ภาษาจาก Accept-Language)
+	if input.Language == "" {
+		i18n.Set(c, i18n.Negotiate(c))
+	}
+	i18n.SetPreferred(c, input.Language)
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.language_updated"), "language": input.Language})
+}
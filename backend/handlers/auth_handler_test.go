@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSignUpLoginAndLogout(t *testing.T) {
+	env := newTestEnv(t)
+	signup := fiber.Map{"name": "Ann", "email": "ann@example.com", "password": "password1"}
+
+	// 1. สมัครสมาชิก และสมัครซ้ำด้วยอีเมลเดิมไม่ได้
+	status, body := env.request("POST", "/signup", signup, nil)
+	wantStatus(t, "signup", status, http.StatusOK, body)
+	status, body = env.request("POST", "/signup", signup, nil)
+	wantStatus(t, "duplicate signup", status, http.StatusConflict, body)
+	wantCode(t, "duplicate signup", body, "email_taken")
+
+	// 2. รหัสผ่านผิดเข้าสู่ระบบไม่ได้
+	status, body = env.request("POST", "/login", fiber.Map{"email": "ann@example.com", "password": "wrong-password"}, nil)
+	wantStatus(t, "login with wrong password", status, http.StatusUnauthorized, body)
+	wantCode(t, "login with wrong password", body, "invalid_credentials")
+
+	// 3. เข้าสู่ระบบได้ Token ที่ใช้เรียก /api ได้
+	status, body = env.request("POST", "/login", fiber.Map{"email": "ann@example.com", "password": "password1"}, nil)
+	wantStatus(t, "login", status, http.StatusOK, body)
+	token, _ := body["token"].(string)
+	if token == "" || body["refresh_token"] == "" {
+		t.Fatalf("login: missing tokens in %v", body)
+	}
+	status, body = env.request("GET", "/api/cart", nil, authed(token))
+	wantStatus(t, "cart with token", status, http.StatusOK, body)
+
+	// 4. หลังออกจากระบบ Token เดิมถูกปฏิเสธ
+	status, body = env.request("POST", "/api/logout", nil, authed(token))
+	wantStatus(t, "logout", status, http.StatusOK, body)
+	status, body = env.request("GET", "/api/cart", nil, authed(token))
+	wantStatus(t, "cart after logout", status, http.StatusUnauthorized, body)
+	wantCode(t, "cart after logout", body, "token_revoked")
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	env := newTestEnv(t)
+
+	status, body := env.request("GET", "/api/cart", nil, nil)
+	wantStatus(t, "cart without token", status, http.StatusUnauthorized, body)
+	wantCode(t, "cart without token", body, "unauthorized")
+
+	status, body = env.request("GET", "/api/cart", nil, authed("not-a-jwt"))
+	wantStatus(t, "cart with bad token", status, http.StatusUnauthorized, body)
+}
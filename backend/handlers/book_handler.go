@@ -1,74 +1,339 @@
 package handlers
 
 import (
-	"my-fiber-app/database" // เรียกใช้ DB
-	"my-fiber-app/models"   // เรียกใช้ Struct
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"     // เรียกใช้ Struct
+	"my-fiber-app/repository" // เรียกใช้ที่เก็บข้อมูล
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// GetBooks: ดึงรายชื่อหนังสือทั้งหมดในระบบ
-func GetBooks(c *fiber.Ctx) error {
-    var books []models.Book
-    if err := database.DB.Find(&books).Error; err != nil {
-        return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถดึงข้อมูลหนังสือได้"})
-    }
-    return c.JSON(books)
+// bookSortKeys: คีย์การเรียงที่อนุญาต
+var bookSortKeys = map[string]bool{
+	"price":      true,
+	"title":      true,
+	"created_at": true,
+}
+
+// BookHandler: จัดการข้อมูลหนังสือ (รายการ ค้นหา และการแก้ไขโดยแอดมิน)
+type BookHandler struct {
+	store        repository.Store
+	books        repository.BookRepository
+	reservations repository.ReservationRepository
+}
+
+// NewBookHandler: สร้าง BookHandler
+func NewBookHandler(store repository.Store) *BookHandler {
+	return &BookHandler{store: store, books: store.Books(), reservations: store.Reservations()}
+}
+
+// สถานะความพร้อมขายของหนังสือ (คำนวณจากสต็อกที่ขายได้)
+const (
+	availabilityInStock    = "in_stock"
+	availabilityLowStock   = "low_stock"
+	availabilityOutOfStock = "out_of_stock"
+)
+
+// lowStockThreshold: สต็อกที่ขายได้เท่านี้หรือน้อยกว่าถือว่า "ใกล้หมด"
+const lowStockThreshold = 5
+
+// BookView: ข้อมูลหนังสือที่ส่งให้หน้าบ้าน พร้อมค่าที่คำนวณเพิ่ม
+type BookView struct {
+	models.Book
+	Available    int    `json:"available"` // สต็อกลบยอดที่ถูกจองไว้ให้คำสั่งซื้อที่รอชำระเงิน
+	Availability string `json:"availability"`
+	InStock      bool   `json:"in_stock"`
+}
+
+// newBookView: เติมค่าที่คำนวณจากข้อมูลหนังสือและยอดที่ถูกจองไว้
+func newBookView(book models.Book, reserved int) BookView {
+	available := max(book.Stock-reserved, 0)
+	availability := availabilityInStock
+	switch {
+	case available <= 0:
+		availability = availabilityOutOfStock
+	case available <= lowStockThreshold:
+		availability = availabilityLowStock
+	}
+	return BookView{Book: book, Available: available, Availability: availability, InStock: available > 0}
+}
+
+// reservedFor: ยอดที่ถูกจองไว้ของหนังสือทุกเล่มในรายการ
+func (h *BookHandler) reservedFor(ctx context.Context, books []models.Book) (map[uint]int, error) {
+	ids := make([]uint, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return h.reservations.Reserved(ctx, ids)
+}
+
+// BookPage: รูปแบบผลลัพธ์ของ GET /books (ทุก Field มีเสมอ เพื่อให้หน้าบ้านพึ่งพาได้)
+type BookPage struct {
+	Data       []BookView `json:"data"`
+	Total      int64      `json:"total"` // จำนวนทั้งหมดที่ตรงกับตัวกรอง
+	Limit      int        `json:"limit"`
+	Page       int        `json:"page"` // 0 เมื่อใช้การแบ่งหน้าแบบ Cursor
+	Sort       string     `json:"sort"`
+	Order      string     `json:"order"`
+	NextCursor *string    `json:"next_cursor"` // null เมื่อไม่มีหน้าถัดไป
+	PrevCursor *string    `json:"prev_cursor"` // null เมื่อไม่มีหน้าก่อนหน้า
+}
+
+// GetBooks: ดึงรายชื่อหนังสือแบบแบ่งหน้า เรียงลำดับ และกรองได้
+// แบ่งหน้าได้ 2 แบบ: ?page=&limit= หรือ ?cursor=&limit= (ใช้ next_cursor/prev_cursor จากผลลัพธ์ก่อนหน้า)
+// เรียงด้วย ?sort=price|title|created_at&order=asc|desc
+// กรองด้วย ?author=&min_price=&max_price=&in_stock=true
+func (h *BookHandler) GetBooks(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. ตรวจสอบการเรียงลำดับและจำนวนต่อหน้า
+	sort := c.Query("sort", "created_at")
+	if !bookSortKeys[sort] {
+		return apperr.ErrInvalidSort.With("allowed", []string{"price", "title", "created_at"})
+	}
+	order := strings.ToLower(c.Query("order", "desc"))
+	if order != "asc" && order != "desc" {
+		return apperr.ErrInvalidSortOrder
+	}
+	limit := clampLimit(c.QueryInt("limit", defaultPageLimit))
+
+	// 2. ตัวกรอง
+	filter := repository.BookFilter{
+		Author:  strings.TrimSpace(c.Query("author")),
+		InStock: c.QueryBool("in_stock"),
+	}
+	if raw := c.Query("min_price"); raw != "" {
+		minPrice, err := strconv.Atoi(raw)
+		if err != nil {
+			return apperr.ErrInvalidPriceFilter.With("param", "min_price")
+		}
+		filter.MinPrice = &minPrice
+	}
+	if raw := c.Query("max_price"); raw != "" {
+		maxPrice, err := strconv.Atoi(raw)
+		if err != nil {
+			return apperr.ErrInvalidPriceFilter.With("param", "max_price")
+		}
+		filter.MaxPrice = &maxPrice
+	}
+
+	// 3. นับจำนวนทั้งหมดที่ตรงกับตัวกรอง (ไม่สนใจหน้า)
+	total, err := h.books.Count(ctx, filter)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 4. เลือกโหมดการแบ่งหน้า
+	var cur *pageCursor
+	page := 0
+	if raw := c.Query("cursor"); raw != "" {
+		decoded, err := decodeCursor(raw)
+		if err != nil || decoded.Sort != sort || decoded.Order != order {
+			return apperr.ErrInvalidCursor
+		}
+		cur = &decoded
+	} else {
+		page = c.QueryInt("page", 1)
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	// ถ้าย้อนกลับ (prev) ต้องสแกนกลับทิศ แล้วค่อยกลับลำดับผลลัพธ์ทีหลัง
+	forward := cur == nil || cur.Direction == cursorNext
+	scanAsc := (order == "asc") == forward
+
+	// 5. ดึงมาเกิน 1 รายการ เพื่อรู้ว่ายังมีหน้าต่อไปหรือไม่
+	query := repository.BookQuery{BookFilter: filter, SortBy: sort, Desc: !scanAsc, Limit: limit + 1}
+	if cur != nil {
+		value, err := cursorValue(sort, cur.Value)
+		if err != nil {
+			return apperr.ErrInvalidCursor
+		}
+		query.After = &repository.BookKey{Value: value, ID: cur.ID}
+	} else {
+		query.Offset = (page - 1) * limit
+	}
+	books, err := h.books.List(ctx, query)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	hasMore := len(books) > limit
+	if hasMore {
+		books = books[:limit]
+	}
+	if !forward {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+	}
+
+	hasNext, hasPrev := hasMore, cur != nil || page > 1
+	if !forward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	// 6. สร้าง Cursor จากรายการแรกและรายการสุดท้ายของหน้านี้
+	reserved, err := h.reservedFor(ctx, books)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	result := BookPage{Data: make([]BookView, 0, len(books)), Total: total, Limit: limit, Page: page, Sort: sort, Order: order}
+	for _, book := range books {
+		result.Data = append(result.Data, newBookView(book, reserved[book.ID]))
+	}
+	if len(books) > 0 {
+		if hasNext {
+			next := encodeCursor(bookCursor(books[len(books)-1], sort, order, cursorNext))
+			result.NextCursor = &next
+		}
+		if hasPrev {
+			prev := encodeCursor(bookCursor(books[0], sort, order, cursorPrev))
+			result.PrevCursor = &prev
+		}
+	}
+
+	// 7. ETag ของหน้านี้เปลี่ยนเมื่อหนังสือในหน้าถูกแก้ไข/ลบ ยอดจองเปลี่ยน หรือจำนวนทั้งหมดเปลี่ยน
+	etagParts := []interface{}{c.Request().URI().QueryArgs().String(), total}
+	for _, book := range books {
+		etagParts = append(etagParts, book.ID, book.UpdatedAt, reserved[book.ID])
+	}
+	if notModified(c, strongETag(etagParts...)) {
+		return nil
+	}
+	return c.JSON(result)
+}
+
+// GetBook: ดึงรายละเอียดหนังสือเล่มเดียว พร้อม Strong ETag จาก UpdatedAt และยอดที่ถูกจองไว้
+// ถ้า Client ส่ง If-None-Match ที่ตรงกับ ETag ปัจจุบัน จะตอบ 304 โดยไม่มี Body
+func (h *BookHandler) GetBook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	book, err := h.books.Get(c.UserContext(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.ErrBookNotFound
+	}
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	reserved, err := h.reservedFor(c.UserContext(), []models.Book{*book})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	if notModified(c, strongETag(book.ID, book.UpdatedAt, reserved[book.ID])) {
+		return nil
+	}
+	return c.JSON(newBookView(*book, reserved[book.ID]))
+}
+
+// bookCursor: สร้าง Cursor ที่ชี้ไปยังหนังสือเล่มนี้ตามคีย์การเรียง
+func bookCursor(book models.Book, sort, order, direction string) pageCursor {
+	var value string
+	switch sort {
+	case "price":
+		value = strconv.Itoa(book.Price)
+	case "created_at":
+		value = book.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		value = book.Title
+	}
+	return pageCursor{Sort: sort, Order: order, Value: value, ID: book.ID, Direction: direction}
 }
 
 // CreateBook: เพิ่มหนังสือเล่มใหม่เข้าไปในระบบ
-func CreateBook(c *fiber.Ctx) error {
-    book := new(models.Book)
-    // 1. รับข้อมูลจากหน้าบ้าน
-    if err := c.BodyParser(book); err != nil {
-        return c.Status(400).JSON(fiber.Map{"error": "ข้อมูลหนังสือไม่ถูกต้อง"})
-    }
-    // 2. บันทึกลงฐานข้อมูล
-    if err := database.DB.Create(&book).Error; err != nil {
-        return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถเพิ่มหนังสือได้"})
-    }
-    return c.JSON(book)
+// สต็อกตั้งต้นถูกบันทึกเป็นการรับของเข้าในสมุดบัญชีสต็อก (Transaction เดียวกับการสร้างหนังสือ)
+func (h *BookHandler) CreateBook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	book := new(models.Book)
+	// 1. รับข้อมูลจากหน้าบ้าน
+	if err := bindBody(c, book); err != nil {
+		return invalidInput(err)
+	}
+	// 2. บันทึกลงฐานข้อมูลด้วยสต็อก 0 แล้วรับของเข้าตามสต็อกที่ส่งมา
+	initialStock := book.Stock
+	book.Stock = 0
+	err := h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Books().Create(ctx, book); err != nil {
+			return err
+		}
+		if initialStock == 0 {
+			return nil
+		}
+		actorID := getUserID(c)
+		return recordStockMovement(ctx, tx, &models.StockMovement{
+			BookID:   book.ID,
+			Type:     models.MovementRestock,
+			Quantity: initialStock,
+			ActorID:  &actorID,
+			Reason:   "initial stock",
+		})
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	book.Stock = initialStock
+	return c.JSON(book)
 }
+
 // ฟังก์ชันแก้ไขข้อมูลหนังสือ (PUT)
-func UpdateBook(c *fiber.Ctx) error {
+func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
 	// 1. รับ ID จาก URL (เช่น /book/1)
-	id := c.Params("id")
-	var book models.Book
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperr.ErrInvalidID
+	}
 
 	// 2. เช็คก่อนว่ามีหนังสือเล่มนี้ไหม?
-	if result := database.DB.First(&book, id); result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"error": "Book not found",
-		})
+	book, err := h.books.Get(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperr.ErrBookNotFound
+	}
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
 	// 3. เตรียมตัวแปรรับค่าที่ส่งมาแก้ไข (เฉพาะ field ที่อนุญาต)
+	// (เงื่อนไขเดียวกับ models.Book)
 	type UpdateBookInput struct {
-		Title    string `json:"title"`
-		Author   string `json:"author"`
-		Price    int    `json:"price"`
-		Description string `json:"description"`
-		ImageURL string `json:"image_url"`
-		Stock    int    `json:"stock"`
+		Title       string `json:"title" validate:"required,min=3,max=255"`
+		Author      string `json:"author" validate:"max=255"`
+		Price       int    `json:"price" validate:"gte=0"`
+		Description string `json:"description" validate:"max=5000"`
+		ImageURL    string `json:"image_url" validate:"max=2048"`
+		Stock       *int   `json:"stock"` // สต็อกแก้ที่ /admin/inventory เท่านั้น ส่งค่าเดิมมาได้ (ฟอร์มที่ส่งทุก Field)
 	}
 	var updateData UpdateBookInput
 
-	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid input",
-		})
+	if err := bindBody(c, &updateData); err != nil {
+		return invalidInput(err)
 	}
 
-	// 4. สั่งอัปเดต (ใช้ Select เพื่อให้อัปเดตค่าที่เป็น 0 หรือค่าว่างได้ด้วย)
-	if err := database.DB.Model(&book).Select("Title", "Author", "Price", "ImageURL", "Stock", "Description").Updates(models.Book{
-		Title:    updateData.Title,
-		Author:   updateData.Author,
-		Price:    updateData.Price,
-		ImageURL: updateData.ImageURL,
-		Stock:    updateData.Stock,
-		Description: updateData.Description,
-	}).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Could not update book"})
+	// สต็อกเปลี่ยนผ่านสมุดบัญชีสต็อกเท่านั้น ไม่ให้เขียนทับจากตรงนี้
+	if updateData.Stock != nil && *updateData.Stock != book.Stock {
+		return apperr.ErrStockManagedByInventory
+	}
+
+	// 4. สั่งอัปเดต (รวมค่าที่เป็น 0 หรือค่าว่างด้วย)
+	book.Title = updateData.Title
+	book.Author = updateData.Author
+	book.Price = updateData.Price
+	book.ImageURL = updateData.ImageURL
+	book.Description = updateData.Description
+	if err := h.books.Update(ctx, book); err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
 	// 5. ส่งข้อมูลล่าสุดกลับไป
@@ -76,22 +341,22 @@ func UpdateBook(c *fiber.Ctx) error {
 }
 
 // ฟังก์ชันลบหนังสือ (DELETE)
-func DeleteBook(c *fiber.Ctx) error {
+func (h *BookHandler) DeleteBook(c *fiber.Ctx) error {
 	// 1. รับ ID
-	id := c.Params("id")
-	var book models.Book
-
-	// 2. เช็คว่ามีไหม (ถ้าไม่มีจะได้บอก User ถูก)
-	if result := database.DB.First(&book, id); result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"error": "Book not found",
-		})
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperr.ErrInvalidID
 	}
 
-	// 3. สั่งลบ (Soft Delete เพราะใช้ gorm.Model)
-	database.DB.Delete(&book)
+	// 2. สั่งลบ (Soft Delete เพราะใช้ gorm.Model) ถ้าไม่มีจะได้บอก User ถูก
+	if err := h.books.Delete(c.UserContext(), uint(id)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrBookNotFound
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
 
 	return c.JSON(fiber.Map{
-		"message": "Book deleted successfully",
+		"message": i18n.T(i18n.From(c), "message.book_deleted"),
 	})
 }
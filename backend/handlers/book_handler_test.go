@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCreateAndUpdateBookAllowFreeBooks(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("admin@example.com")
+
+	// 1. หนังสือราคา 0 (แจกฟรี) สร้างได้
+	status, body := env.request("POST", "/admin/book", fiber.Map{"title": "Free Book", "price": 0, "stock": 1}, authed(token))
+	wantStatus(t, "create free book", status, http.StatusOK, body)
+	id := uint(body["ID"].(float64))
+
+	// 2. เปลี่ยนราคาเป็น 0 ได้ และราคาติดลบยังถูกปฏิเสธ
+	status, body = env.request("PUT", fmt.Sprintf("/admin/book/%d", id), fiber.Map{"title": "Free Book", "price": 0}, authed(token))
+	wantStatus(t, "update price to 0", status, http.StatusOK, body)
+	status, body = env.request("PUT", fmt.Sprintf("/admin/book/%d", id), fiber.Map{"title": "Free Book", "price": -1}, authed(token))
+	wantStatus(t, "negative price", status, http.StatusUnprocessableEntity, body)
+	status, body = env.request("POST", "/admin/book", fiber.Map{"title": "Bad Book", "price": -5}, authed(token))
+	wantStatus(t, "create with negative price", status, http.StatusUnprocessableEntity, body)
+}
+
+func TestBookRoutesRejectInvalidID(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("admin@example.com")
+	update := fiber.Map{"title": "Go in Action", "price": 300}
+
+	for _, tc := range []struct {
+		method, path string
+		body         interface{}
+	}{
+		{"GET", "/books/abc", nil},
+		{"PUT", "/admin/book/abc", update},
+		{"PUT", "/admin/book/0", update},
+		{"DELETE", "/admin/book/abc", nil},
+	} {
+		status, body := env.request(tc.method, tc.path, tc.body, authed(token))
+		wantStatus(t, tc.method+" "+tc.path, status, http.StatusBadRequest, body)
+		wantCode(t, tc.method+" "+tc.path, body, "invalid_id")
+	}
+}
+
+// failingBooksStore: ที่เก็บข้อมูลที่อ่านหนังสือไม่ได้ (ฐานข้อมูลล่ม) ใช้แยก "ไม่พบ" ออกจาก Error อื่น
+type failingBooksStore struct {
+	repository.Store
+}
+
+func (s failingBooksStore) Books() repository.BookRepository {
+	return failingBooks{s.Store.Books()}
+}
+
+type failingBooks struct {
+	repository.BookRepository
+}
+
+func (failingBooks) Get(context.Context, uint) (*models.Book, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestBookLookupErrorIsNotNotFound(t *testing.T) {
+	env := newTestEnvWith(t, func(memory *repository.MemoryStore) repository.Store {
+		return failingBooksStore{memory}
+	})
+	_, token := env.createUser("admin@example.com")
+
+	status, body := env.request("GET", "/books/1", nil, nil)
+	wantStatus(t, "get book", status, http.StatusInternalServerError, body)
+	wantCode(t, "get book", body, "internal_error")
+
+	status, body = env.request("PUT", "/admin/book/1", fiber.Map{"title": "Go in Action", "price": 300}, authed(token))
+	wantStatus(t, "update book", status, http.StatusInternalServerError, body)
+	wantCode(t, "update book", body, "internal_error")
+}
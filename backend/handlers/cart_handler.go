@@ -1,13 +1,29 @@
 package handlers
 
 import (
-	"my-fiber-app/database"
+	"errors"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
 	"my-fiber-app/models"
+	"my-fiber-app/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// CartHandler: จัดการตะกร้าสินค้าของผู้ใช้
+type CartHandler struct {
+	carts        repository.CartRepository
+	books        repository.BookRepository
+	reservations repository.ReservationRepository
+}
+
+// NewCartHandler: สร้าง CartHandler
+func NewCartHandler(carts repository.CartRepository, books repository.BookRepository, reservations repository.ReservationRepository) *CartHandler {
+	return &CartHandler{carts: carts, books: books, reservations: reservations}
+}
+
 // getUserID: ฟังก์ชันช่วยสำหรับดึง User ID จาก Token ที่ส่งมากับ Request
 func getUserID(c *fiber.Ctx) uint {
 	userToken := c.Locals("user").(*jwt.Token)
@@ -17,115 +33,114 @@ func getUserID(c *fiber.Ctx) uint {
 }
 
 // AddToCart: เพิ่มสินค้าลงในตะกร้าของผู้ใช้
-func AddToCart(c *fiber.Ctx) error {
+func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
 	userID := getUserID(c)
+	ctx := c.UserContext()
 
 	type CartInput struct {
-		BookID   uint `json:"book_id"`
-		Quantity int  `json:"quantity"`
+		BookID   uint `json:"book_id" validate:"required"`
+		Quantity int  `json:"quantity" validate:"required,gte=1,lte=999"`
 	}
 	input := new(CartInput)
-	if err := c.BodyParser(input); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
-	}
-
-	// 1. ตรวจสอบจำนวนสินค้าที่ส่งมา
-	if input.Quantity <= 0 {
-		return c.Status(400).JSON(fiber.Map{"error": "จำนวนสินค้าต้องมากกว่า 0"})
-	}
-
-	// 2. ตรวจสอบว่ามีหนังสือจริงและสต็อกเพียงพอไหม
-	var book models.Book
-	if err := database.DB.First(&book, input.BookID).Error; err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "ไม่พบหนังสือที่ต้องการ"})
-	}
-
-	if book.Stock < input.Quantity {
-		return c.Status(400).JSON(fiber.Map{"error": "จำนวนสินค้าในคลังไม่พอ"})
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
 	}
 
-	// 3. ตรวจสอบว่าเคยมีในตะกร้าแล้วหรือยัง
-	var cartItem models.CartItem
-	result := database.DB.Where("user_id = ? AND book_id = ?", userID, input.BookID).First(&cartItem)
-
-	if result.Error == nil {
-		// ถ้ามีอยู่แล้วให้บวกเพิ่ม
-		cartItem.Quantity += input.Quantity
-		database.DB.Save(&cartItem)
-	} else {
-		// ถ้ายังไม่มีให้สร้างใหม่
-		newItem := models.CartItem{
-			UserID:   userID,
-			BookID:   input.BookID,
-			Quantity: input.Quantity,
+	// 1. จำนวนสินค้าถูกตรวจแล้วตาม Tag validate (1–999)
+	// 2. เพิ่มลงตะกร้า ถ้าเคยมีในตะกร้าแล้วให้บวกเพิ่มใน SQL คำสั่งเดียว (Request ที่มาพร้อมกันไม่สร้างรายการซ้ำหรือทำยอดหาย)
+	// โดยตรวจว่าจำนวนรวมในตะกร้าไม่เกินสต็อกที่ขายได้ (สต็อกลบยอดที่ถูกจองไว้) ในคำสั่งเดียวกัน
+	if _, err := h.carts.Add(ctx, userID, input.BookID, input.Quantity); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return apperr.ErrBookNotFound
+		case errors.Is(err, repository.ErrStockExceeded):
+			return apperr.ErrInsufficientStock
 		}
-		database.DB.Create(&newItem)
+		return apperr.ErrInternal.Wrap(err)
 	}
 
-	return c.JSON(fiber.Map{"message": "เพิ่มสินค้าลงตะกร้าสำเร็จ"})
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.cart_item_added")})
 }
 
 // GetCart: ดึงรายการสินค้าทั้งหมดในตะกร้าของผู้ใช้คนนั้นๆ
-func GetCart(c *fiber.Ctx) error {
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
 	userID := getUserID(c)
-	var cartItems []models.CartItem
 
-	// ใช้ Preload("Book") เพื่อดึงรายละเอียดข้อมูลหนังสือมาพร้อมกัน
-	if err := database.DB.Where("user_id = ?", userID).Preload("Book").Find(&cartItems).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถดึงข้อมูลตะกร้าได้"})
+	// ดึงรายละเอียดข้อมูลหนังสือมาพร้อมกัน
+	cartItems, err := h.carts.List(c.UserContext(), userID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
+	if cartItems == nil {
+		cartItems = []models.CartItem{}
+	}
 	return c.JSON(cartItems)
 }
 
 // DeleteCartItem: ลบสินค้าที่ต้องการออกจากตะกร้า
-func DeleteCartItem(c *fiber.Ctx) error {
+func (h *CartHandler) DeleteCartItem(c *fiber.Ctx) error {
 	userID := getUserID(c)
-	itemID := c.Params("id") // รับ ID ของรายการในตะกร้า (CartItem ID)
+	itemID, err := c.ParamsInt("id") // รับ ID ของรายการในตะกร้า (CartItem ID)
+	if err != nil || itemID <= 0 {
+		return apperr.ErrInvalidID
+	}
 
 	// ลบโดยตรวจสอบว่าเป็นของเจ้าของ User จริงๆ เพื่อความปลอดภัย
-	result := database.DB.Where("id = ? AND user_id = ?", itemID, userID).Delete(&models.CartItem{})
-
-	if result.RowsAffected == 0 {
-		return c.Status(404).JSON(fiber.Map{"error": "ไม่พบสินค้าในตะกร้า"})
+	if err := h.carts.Delete(c.UserContext(), userID, uint(itemID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrCartItemNotFound
+		}
+		return apperr.ErrInternal.Wrap(err)
 	}
 
-	return c.JSON(fiber.Map{"message": "ลบสินค้าออกจากตะกร้าสำเร็จ"})
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.cart_item_removed")})
 }
 
 // UpdateCartItem: อัปเดตจำนวนสินค้าในตะกร้า (กำหนดค่าทับลงไปเลย)
-func UpdateCartItem(c *fiber.Ctx) error {
+func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
 	userID := getUserID(c)
-	itemID := c.Params("id") // รับ ID ของรายการในตะกร้า (CartItem ID)
+	ctx := c.UserContext()
+	itemID, err := c.ParamsInt("id") // รับ ID ของรายการในตะกร้า (CartItem ID)
+	if err != nil || itemID <= 0 {
+		return apperr.ErrInvalidID
+	}
 
 	type UpdateInput struct {
-		Quantity int `json:"quantity"`
+		Quantity int `json:"quantity" validate:"required,gte=1,lte=999"`
 	}
 	input := new(UpdateInput)
-	if err := c.BodyParser(input); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
 	}
 
-	if input.Quantity <= 0 {
-		return c.Status(400).JSON(fiber.Map{"error": "จำนวนสินค้าต้องมากกว่า 0"})
-	}
-
-	var cartItem models.CartItem
 	// ค้นหาด้วย ID ของรายการเอง จะแม่นยำกว่า
-	result := database.DB.Where("id = ? AND user_id = ?", itemID, userID).First(&cartItem)
+	cartItem, err := h.carts.Get(ctx, userID, uint(itemID))
+	if err != nil {
+		return apperr.ErrCartItemNotFound
+	}
 
-	if result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "ไม่พบสินค้าในตะกร้า"})
+	// ตรวจสต็อกที่ขายได้ (สต็อกลบยอดที่ถูกจองไว้) กับจำนวนใหม่
+	book, err := h.books.Get(ctx, cartItem.BookID)
+	if err != nil {
+		return apperr.ErrBookNotFound
+	}
+	available, err := availableStock(ctx, h.reservations, book)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if input.Quantity > available {
+		return apperr.ErrInsufficientStock
 	}
 
 	// อัปเดตจำนวนเป็นค่าใหม่ที่ส่งมา
 	cartItem.Quantity = input.Quantity
-	if err := database.DB.Save(&cartItem).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "ไม่สามารถบันทึกข้อมูลได้"})
+	if err := h.carts.Save(ctx, cartItem); err != nil {
+		return apperr.ErrInternal.Wrap(err)
 	}
 
 	return c.JSON(fiber.Map{
-		"message":  "อัปเดตจำนวนสินค้าสำเร็จ",
+		"message":  i18n.T(i18n.From(c), "message.cart_item_updated"),
 		"quantity": cartItem.Quantity,
 	})
 }
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// cartItems: รายการในตะกร้าของเจ้าของ Token
+func (e *testEnv) cartItems(token string) []models.CartItem {
+	e.t.Helper()
+	resp := e.send("GET", "/api/cart", nil, authed(token))
+	defer resp.Body.Close()
+	var items []models.CartItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		e.t.Fatal(err)
+	}
+	return items
+}
+
+func TestAddToCartAccumulatesUpToStock(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	add := fiber.Map{"book_id": book.ID, "quantity": 2}
+
+	// 1. เพิ่มเล่มเดิมสองครั้ง ได้รายการเดียวที่จำนวนรวมกัน
+	for i := 0; i < 2; i++ {
+		status, body := env.request("POST", "/api/cart", add, authed(token))
+		wantStatus(t, "add to cart", status, http.StatusOK, body)
+	}
+	items := env.cartItems(token)
+	if len(items) != 1 || items[0].Quantity != 4 {
+		t.Fatalf("cart = %+v, want one line with quantity 4", items)
+	}
+
+	// 2. เพิ่มจนเกินสต็อกไม่ได้ และตะกร้าไม่เปลี่ยน
+	status, body := env.request("POST", "/api/cart", add, authed(token))
+	wantStatus(t, "add beyond stock", status, http.StatusConflict, body)
+	wantCode(t, "add beyond stock", body, "insufficient_stock")
+	if items := env.cartItems(token); items[0].Quantity != 4 {
+		t.Fatalf("quantity after rejected add = %d, want 4", items[0].Quantity)
+	}
+
+	// 3. หนังสือที่ไม่มีอยู่
+	status, body = env.request("POST", "/api/cart", fiber.Map{"book_id": 999, "quantity": 1}, authed(token))
+	wantStatus(t, "add missing book", status, http.StatusNotFound, body)
+	wantCode(t, "add missing book", body, "book_not_found")
+}
+
+func TestUpdateCartItemChecksAvailableStock(t *testing.T) {
+	env := newTestEnv(t)
+	_, buyer := env.createUser("buyer@example.com")
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+
+	// คนอื่นสั่งซื้อไป 3 เล่ม (จองไว้) เหลือที่ขายได้ 2 เล่ม
+	env.checkout(buyer, book.ID, 3)
+
+	status, body := env.request("POST", "/api/cart", fiber.Map{"book_id": book.ID, "quantity": 1}, authed(token))
+	wantStatus(t, "add to cart", status, http.StatusOK, body)
+	itemPath := fmt.Sprintf("/api/cart/%d", env.cartItems(token)[0].ID)
+
+	status, body = env.request("PUT", itemPath, fiber.Map{"quantity": 3}, authed(token))
+	wantStatus(t, "update beyond available", status, http.StatusConflict, body)
+	wantCode(t, "update beyond available", body, "insufficient_stock")
+
+	status, body = env.request("PUT", itemPath, fiber.Map{"quantity": 2}, authed(token))
+	wantStatus(t, "update within available", status, http.StatusOK, body)
+	if items := env.cartItems(token); items[0].Quantity != 2 {
+		t.Fatalf("quantity = %d, want 2", items[0].Quantity)
+	}
+}
+
+func TestCartRoutesRejectInvalidID(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+
+	status, body := env.request("PUT", "/api/cart/abc", fiber.Map{"quantity": 1}, authed(token))
+	wantStatus(t, "update cart item abc", status, http.StatusBadRequest, body)
+	wantCode(t, "update cart item abc", body, "invalid_id")
+
+	status, body = env.request("DELETE", "/api/cart/abc", nil, authed(token))
+	wantStatus(t, "delete cart item abc", status, http.StatusBadRequest, body)
+	wantCode(t, "delete cart item abc", body, "invalid_id")
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// strongETag: สร้าง Strong ETag (อยู่ในเครื่องหมายคำพูด) จากส่วนประกอบที่เปลี่ยนเมื่อข้อมูลเปลี่ยน
+func strongETag(parts ...interface{}) string {
+	h := sha256.New()
+	for _, p := range parts {
+		if t, ok := p.(time.Time); ok {
+			// ใช้ UnixNano เพื่อไม่ให้ Timezone หรือรูปแบบการแสดงผลมีผลต่อค่า
+			p = t.UnixNano()
+		}
+		fmt.Fprintf(h, "%v|", p)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// etagMatches: ตรวจสอบ Header If-None-Match ว่าตรงกับ ETag ปัจจุบันหรือไม่
+// รองรับหลายค่าคั่นด้วยจุลภาค, "*" และค่าที่ขึ้นต้นด้วย W/ (เทียบแบบ Weak ตาม RFC 9110)
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified: ใส่ ETag ลงใน Response และตอบ 304 ถ้า Client มีข้อมูลล่าสุดอยู่แล้ว
+// คืนค่า true เมื่อตอบ 304 ไปแล้ว (Handler ไม่ต้องส่ง Body ต่อ)
+func notModified(c *fiber.Ctx, etag string) bool {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if inm := c.Get(fiber.HeaderIfNoneMatch); inm != "" && etagMatches(inm, etag) {
+		c.Status(fiber.StatusNotModified)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ช่องทางที่ Client ส่ง Token ของตะกร้า Guest มา (Header สำหรับเว็บที่อยู่คนละ Origin หรือแอปมือถือ)
+const (
+	guestCartCookie = "guest_cart"
+	guestCartHeader = "X-Guest-Cart"
+)
+
+// อายุของตะกร้า Guest นับจากการใช้งานล่าสุด
+const guestCartTTL = 30 * 24 * time.Hour
+
+// guestCartKey: กุญแจสำหรับเซ็น Token ของตะกร้า Guest แยกจากกุญแจอื่น (Derive จาก JWT Secret)
+func guestCartKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("guest-cart"))
+	return mac.Sum(nil)
+}
+
+// signGuestCart: Token ของตะกร้า (id.signature แบบ base64url)
+func signGuestCart(secret []byte, cartID string) string {
+	mac := hmac.New(sha256.New, guestCartKey(secret))
+	mac.Write([]byte(cartID))
+	return cartID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// guestCartID: ID ของตะกร้าจาก Header X-Guest-Cart หรือ Cookie (Token ที่ลายเซ็นไม่ถูกต้องถือว่าไม่มีตะกร้า)
+func guestCartID(c *fiber.Ctx, secret []byte) (string, bool) {
+	token := c.Get(guestCartHeader)
+	if token == "" {
+		token = c.Cookies(guestCartCookie)
+	}
+	cartID, signature, ok := strings.Cut(token, ".")
+	if !ok || cartID == "" {
+		return "", false
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", false
+	}
+	mac := hmac.New(sha256.New, guestCartKey(secret))
+	mac.Write([]byte(cartID))
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return "", false
+	}
+	return cartID, true
+}
+
+// setGuestCartToken: ส่ง Token ของตะกร้าให้ Client ทั้งทาง Cookie และ Header
+func setGuestCartToken(c *fiber.Ctx, secret []byte, cartID string) {
+	token := signGuestCart(secret, cartID)
+	c.Cookie(&fiber.Cookie{
+		Name:     guestCartCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(guestCartTTL.Seconds()),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	c.Set(guestCartHeader, token)
+}
+
+// clearGuestCartToken: ลบ Cookie ของตะกร้า (หลังรวมเข้าตะกร้าของผู้ใช้แล้ว)
+func clearGuestCartToken(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     guestCartCookie,
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+// GuestCartHandler: ตะกร้าสินค้าของผู้ที่ยังไม่ได้เข้าสู่ระบบ (เก็บในฐานข้อมูล อ้างอิงด้วย Token ที่เซ็นแล้ว)
+type GuestCartHandler struct {
+	store  repository.Store
+	secret []byte // JWT Secret ที่ใช้ Derive กุญแจเซ็น Token ของตะกร้า
+}
+
+// NewGuestCartHandler: สร้าง GuestCartHandler (jwtSecret ต้องตรงกับของ AuthHandler เพื่อให้รวมตะกร้าตอนเข้าสู่ระบบได้)
+func NewGuestCartHandler(store repository.Store, jwtSecret string) *GuestCartHandler {
+	return &GuestCartHandler{store: store, secret: []byte(jwtSecret)}
+}
+
+// GetCart: รายการในตะกร้า Guest (ยังไม่มีตะกร้า = รายการว่าง)
+func (h *GuestCartHandler) GetCart(c *fiber.Ctx) error {
+	items := []models.GuestCartItem{}
+	if cartID, ok := guestCartID(c, h.secret); ok {
+		stored, err := h.store.GuestCarts().List(c.UserContext(), cartID)
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		if stored != nil {
+			items = stored
+		}
+	}
+	return c.JSON(items)
+}
+
+// AddToCart: เพิ่มหนังสือลงตะกร้า Guest ถ้ายังไม่มีตะกร้าจะสร้างใหม่และส่ง Token กลับไป
+func (h *GuestCartHandler) AddToCart(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	type CartInput struct {
+		BookID   uint `json:"book_id" validate:"required"`
+		Quantity int  `json:"quantity" validate:"required,gte=1,lte=999"`
+	}
+	input := new(CartInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	// 1. ตรวจสอบว่ามีหนังสือจริงและสต็อกที่ขายได้เพียงพอไหม
+	book, err := h.store.Books().Get(ctx, input.BookID)
+	if err != nil {
+		return apperr.ErrBookNotFound
+	}
+	available, err := availableStock(ctx, h.store.Reservations(), book)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if available < input.Quantity {
+		return apperr.ErrInsufficientStock
+	}
+
+	// 2. ใช้ตะกร้าเดิม หรือสร้างตะกร้าใหม่ (ถือโอกาสลบตะกร้าที่ถูกทิ้งไว้นานแล้ว)
+	now := time.Now()
+	cartID, ok := guestCartID(c, h.secret)
+	if !ok {
+		if cartID, err = randomToken(); err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		if err := h.store.GuestCarts().PurgeStale(ctx, now.Add(-guestCartTTL)); err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	// 3. เพิ่มลงตะกร้า ถ้ามีอยู่แล้วให้บวกเพิ่ม (ยอดรวมในตะกร้าต้องไม่เกินสต็อกที่ขายได้)
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.GuestCarts().Touch(ctx, cartID, now); err != nil {
+			return err
+		}
+		item, err := tx.GuestCarts().FindByBook(ctx, cartID, input.BookID)
+		switch {
+		case err == nil:
+			if item.Quantity+input.Quantity > available {
+				return repository.ErrStockExceeded
+			}
+			item.Quantity += input.Quantity
+			return tx.GuestCarts().SaveItem(ctx, item)
+		case errors.Is(err, repository.ErrNotFound):
+			return tx.GuestCarts().CreateItem(ctx, &models.GuestCartItem{
+				GuestCartID: cartID,
+				BookID:      input.BookID,
+				Quantity:    input.Quantity,
+			})
+		default:
+			return err
+		}
+	})
+	if errors.Is(err, repository.ErrStockExceeded) {
+		return apperr.ErrInsufficientStock
+	}
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	setGuestCartToken(c, h.secret, cartID)
+	return c.JSON(fiber.Map{
+		"message":     i18n.T(i18n.From(c), "message.cart_item_added"),
+		"guest_token": signGuestCart(h.secret, cartID),
+	})
+}
+
+// UpdateCartItem: ตั้งจำนวนของรายการในตะกร้า Guest
+func (h *GuestCartHandler) UpdateCartItem(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	itemID, err := c.ParamsInt("id")
+	if err != nil || itemID <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	type UpdateInput struct {
+		Quantity int `json:"quantity" validate:"required,gte=1,lte=999"`
+	}
+	input := new(UpdateInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	cartID, ok := guestCartID(c, h.secret)
+	if !ok {
+		return apperr.ErrCartItemNotFound
+	}
+	item, err := h.store.GuestCarts().Get(ctx, cartID, uint(itemID))
+	if err != nil {
+		return apperr.ErrCartItemNotFound
+	}
+
+	// ตรวจสต็อกที่ขายได้ (สต็อกลบยอดที่ถูกจองไว้) กับจำนวนใหม่ เหมือนตะกร้าของผู้ใช้
+	book, err := h.store.Books().Get(ctx, item.BookID)
+	if err != nil {
+		return apperr.ErrBookNotFound
+	}
+	available, err := availableStock(ctx, h.store.Reservations(), book)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if input.Quantity > available {
+		return apperr.ErrInsufficientStock
+	}
+
+	item.Quantity = input.Quantity
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.GuestCarts().Touch(ctx, cartID, time.Now()); err != nil {
+			return err
+		}
+		return tx.GuestCarts().SaveItem(ctx, item)
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  i18n.T(i18n.From(c), "message.cart_item_updated"),
+		"quantity": item.Quantity,
+	})
+}
+
+// DeleteCartItem: ลบรายการออกจากตะกร้า Guest
+func (h *GuestCartHandler) DeleteCartItem(c *fiber.Ctx) error {
+	itemID, err := c.ParamsInt("id")
+	if err != nil || itemID <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	cartID, ok := guestCartID(c, h.secret)
+	if !ok {
+		return apperr.ErrCartItemNotFound
+	}
+	if err := h.store.GuestCarts().DeleteItem(c.UserContext(), cartID, uint(itemID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrCartItemNotFound
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.cart_item_removed")})
+}
+
+// mergeGuestCart: รวมตะกร้า Guest ของ Request นี้ (ถ้ามี) เข้าตะกร้าของผู้ใช้ แล้วลบตะกร้า Guest ทิ้ง
+// ใช้ตอนเข้าสู่ระบบและสมัครสมาชิก แต่ละเล่มเพิ่มด้วย Carts().Add เหมือน AddToCart
+// โดยตัดจำนวนให้ยอดรวมในตะกร้าไม่เกินสต็อกที่ขายได้ (เล่มที่ถูกลบหรือหมดสต็อกถูกข้ามไป) คืนจำนวนเล่มที่รวมเข้าไป
+func mergeGuestCart(c *fiber.Ctx, store repository.Store, secret []byte, userID uint) (int, error) {
+	cartID, ok := guestCartID(c, secret)
+	if !ok {
+		return 0, nil
+	}
+	ctx := c.UserContext()
+
+	merged := 0
+	err := store.Transaction(ctx, func(tx repository.Store) error {
+		items, err := tx.GuestCarts().List(ctx, cartID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			book, err := tx.Books().Get(ctx, item.BookID)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			available, err := availableStock(ctx, tx.Reservations(), book)
+			if err != nil {
+				return err
+			}
+			quantity := item.Quantity
+			if existing, err := tx.Carts().FindByBook(ctx, userID, item.BookID); err == nil {
+				quantity = min(quantity, available-existing.Quantity)
+			} else if errors.Is(err, repository.ErrNotFound) {
+				quantity = min(quantity, available)
+			} else {
+				return err
+			}
+			if quantity <= 0 {
+				continue
+			}
+			_, err = tx.Carts().Add(ctx, userID, item.BookID, quantity)
+			if errors.Is(err, repository.ErrStockExceeded) {
+				continue // ตะกร้าของผู้ใช้ถูกเพิ่มพร้อมกันจากที่อื่นจนเต็มสต็อกแล้ว
+			}
+			if err != nil {
+				return err
+			}
+			merged++
+		}
+		return tx.GuestCarts().Delete(ctx, cartID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	clearGuestCartToken(c)
+	return merged, nil
+}
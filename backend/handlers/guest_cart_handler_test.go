@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// guestCartItems: รายการในตะกร้า Guest ของ Token นี้
+func (e *testEnv) guestCartItems(guestToken string) []models.GuestCartItem {
+	e.t.Helper()
+	resp := e.send("GET", "/guest-cart", nil, map[string]string{guestCartHeader: guestToken})
+	defer resp.Body.Close()
+	var items []models.GuestCartItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		e.t.Fatal(err)
+	}
+	return items
+}
+
+func TestGuestCartChecksAvailableStock(t *testing.T) {
+	env := newTestEnv(t)
+	book := env.createBook("Go in Action", 300, 3)
+	add := fiber.Map{"book_id": book.ID, "quantity": 2}
+
+	// 1. ใส่ครั้งแรกได้ ครั้งที่สองยอดรวม 4 เกินสต็อก 3 ถูกปฏิเสธ และตะกร้าไม่เปลี่ยน
+	status, body := env.request("POST", "/guest-cart", add, nil)
+	wantStatus(t, "add to guest cart", status, http.StatusOK, body)
+	guest := map[string]string{guestCartHeader: body["guest_token"].(string)}
+
+	status, body = env.request("POST", "/guest-cart", add, guest)
+	wantStatus(t, "add beyond stock", status, http.StatusConflict, body)
+	wantCode(t, "add beyond stock", body, "insufficient_stock")
+	items := env.guestCartItems(guest[guestCartHeader])
+	if len(items) != 1 || items[0].Quantity != 2 {
+		t.Fatalf("guest cart = %+v, want one line with quantity 2", items)
+	}
+
+	// 2. แก้จำนวนเกินสต็อกไม่ได้ แก้ภายในสต็อกได้
+	itemPath := fmt.Sprintf("/guest-cart/%d", items[0].ID)
+	status, body = env.request("PUT", itemPath, fiber.Map{"quantity": 900}, guest)
+	wantStatus(t, "update beyond stock", status, http.StatusConflict, body)
+	wantCode(t, "update beyond stock", body, "insufficient_stock")
+
+	status, body = env.request("PUT", itemPath, fiber.Map{"quantity": 3}, guest)
+	wantStatus(t, "update within stock", status, http.StatusOK, body)
+	if items := env.guestCartItems(guest[guestCartHeader]); items[0].Quantity != 3 {
+		t.Fatalf("quantity = %d, want 3", items[0].Quantity)
+	}
+}
+
+func TestGuestCartRoutesRejectInvalidID(t *testing.T) {
+	env := newTestEnv(t)
+	book := env.createBook("Go in Action", 300, 3)
+	status, body := env.request("POST", "/guest-cart", fiber.Map{"book_id": book.ID, "quantity": 1}, nil)
+	wantStatus(t, "add to guest cart", status, http.StatusOK, body)
+	guest := map[string]string{guestCartHeader: body["guest_token"].(string)}
+
+	status, body = env.request("PUT", "/guest-cart/abc", fiber.Map{"quantity": 1}, guest)
+	wantStatus(t, "update guest item abc", status, http.StatusBadRequest, body)
+	wantCode(t, "update guest item abc", body, "invalid_id")
+
+	status, body = env.request("DELETE", "/guest-cart/abc", nil, guest)
+	wantStatus(t, "delete guest item abc", status, http.StatusBadRequest, body)
+	wantCode(t, "delete guest item abc", body, "invalid_id")
+}
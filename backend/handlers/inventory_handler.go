@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errZeroMovement: การเคลื่อนไหวที่ไม่ทำให้สต็อกเปลี่ยน ไม่มีประโยชน์ที่จะบันทึก
+var errZeroMovement = errors.New("stock movement quantity is zero")
+
+// stockBelowReservedError: การนำของออกจะทำให้สต็อกเหลือน้อยกว่ายอดที่ถูกจองไว้
+type stockBelowReservedError struct {
+	Available int // จำนวนที่นำออกได้มากที่สุด
+}
+
+func (e *stockBelowReservedError) Error() string {
+	return "stock would fall below reserved quantity"
+}
+
+// recordStockMovement: เปลี่ยนสต็อกของหนังสือพร้อมบันทึกลงสมุดบัญชีสต็อก (ใช้ภายใน Transaction)
+// ทุกการเปลี่ยน Book.Stock ต้องผ่านฟังก์ชันนี้ ผลรวมของสมุดบัญชีจึงตรงกับสต็อกเสมอ
+// การนำของออกที่ไม่ได้มาจากคำสั่งซื้อ ห้ามกินสต็อกที่ถูกจองไว้ให้คำสั่งซื้อที่รอชำระเงิน
+func recordStockMovement(ctx context.Context, tx repository.Store, m *models.StockMovement) error {
+	if m.Quantity == 0 {
+		return errZeroMovement
+	}
+
+	// 1. ล็อกแถวหนังสือไว้ เพื่อให้ StockAfter ตรงกับลำดับการบันทึกจริง
+	books, err := tx.Books().LockForUpdate(ctx, []uint{m.BookID})
+	if err != nil {
+		return err
+	}
+	if len(books) == 0 {
+		return repository.ErrNotFound
+	}
+	book := books[0]
+
+	// 2. ตรวจว่าสต็อกหลังนำออกยังพอสำหรับยอดที่ถูกจองไว้
+	stockAfter := book.Stock + m.Quantity
+	if m.Quantity < 0 && m.OrderID == nil {
+		reserved, err := tx.Reservations().Reserved(ctx, []uint{book.ID})
+		if err != nil {
+			return err
+		}
+		if stockAfter < reserved[book.ID] {
+			return &stockBelowReservedError{Available: max(book.Stock-reserved[book.ID], 0)}
+		}
+	}
+
+	// 3. เปลี่ยนสต็อกและบันทึกการเคลื่อนไหว
+	if err := tx.Books().AdjustStock(ctx, book.ID, m.Quantity); err != nil {
+		return err
+	}
+	m.StockAfter = stockAfter
+	return tx.StockMovements().Create(ctx, m)
+}
+
+// InventoryHandler: สมุดบัญชีสต็อกสำหรับผู้ดูแล (รับของเข้า ปรับยอด ตัดของเสีย และดูประวัติ)
+type InventoryHandler struct {
+	store repository.Store
+}
+
+// NewInventoryHandler: สร้าง InventoryHandler
+func NewInventoryHandler(store repository.Store) *InventoryHandler {
+	return &InventoryHandler{store: store}
+}
+
+// CreateMovement: บันทึกการเคลื่อนไหวของสต็อกหนึ่งรายการ
+// ชนิดที่มีทิศทางตายตัว (restock, return เพิ่ม / sale, damage ลด) รับจำนวนเป็นบวก ส่วน adjustment ระบุเครื่องหมายเอง
+func (h *InventoryHandler) CreateMovement(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	type MovementInput struct {
+		BookID   uint   `json:"book_id" validate:"required"`
+		Type     string `json:"type" validate:"required,oneof=restock sale return adjustment damage"`
+		Quantity int    `json:"quantity" validate:"required,gte=-100000,lte=100000"`
+		Reason   string `json:"reason" validate:"required,max=500"`
+	}
+	input := new(MovementInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	// 1. แปลงจำนวนให้มีเครื่องหมายตามชนิด
+	quantity := input.Quantity
+	if sign := models.MovementSign(input.Type); sign != 0 {
+		if quantity < 0 {
+			return apperr.ErrInvalidMovementQuantity
+		}
+		quantity *= sign
+	}
+
+	// 2. บันทึกลงสมุดบัญชีพร้อมเปลี่ยนสต็อก
+	actorID := getUserID(c)
+	movement := &models.StockMovement{
+		BookID:   input.BookID,
+		Type:     input.Type,
+		Quantity: quantity,
+		ActorID:  &actorID,
+		Reason:   input.Reason,
+	}
+	err := h.store.Transaction(ctx, func(tx repository.Store) error {
+		return recordStockMovement(ctx, tx, movement)
+	})
+	if err != nil {
+		var shortfall *stockBelowReservedError
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return apperr.ErrBookNotFound
+		case errors.As(err, &shortfall):
+			return apperr.ErrInsufficientStock.With("available", shortfall.Available)
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  i18n.T(i18n.From(c), "message.stock_movement_recorded"),
+		"movement": movement,
+	})
+}
+
+// GetMovements: ประวัติการเคลื่อนไหวของสต็อก ใหม่สุดก่อน กรองตามหนังสือและชนิดได้
+func (h *InventoryHandler) GetMovements(c *fiber.Ctx) error {
+	type MovementQuery struct {
+		BookID uint   `query:"book_id" json:"book_id"`
+		Type   string `query:"type" json:"type"`
+	}
+	input := new(MovementQuery)
+	if err := c.QueryParser(input); err != nil {
+		return apperr.ErrBadRequest.Wrap(err)
+	}
+	if input.Type != "" && !slices.Contains(models.MovementTypes, input.Type) {
+		return apperr.ErrBadRequest
+	}
+
+	return h.listMovements(c, repository.StockMovementFilter{BookID: input.BookID, Type: input.Type}, nil)
+}
+
+// GetBookInventory: สรุปสต็อกของหนังสือหนึ่งเล่ม (สต็อก ยอดจอง ที่ขายได้ ผลรวมสมุดบัญชี) พร้อมประวัติของเล่มนั้น
+func (h *InventoryHandler) GetBookInventory(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	book, err := h.store.Books().Get(ctx, uint(id))
+	if err != nil {
+		return apperr.ErrBookNotFound
+	}
+	reserved, err := h.store.Reservations().Reserved(ctx, []uint{book.ID})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	ledgerTotal, err := h.store.StockMovements().Total(ctx, book.ID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return h.listMovements(c, repository.StockMovementFilter{BookID: book.ID}, fiber.Map{
+		"book_id":      book.ID,
+		"title":        book.Title,
+		"stock":        book.Stock,
+		"reserved":     reserved[book.ID],
+		"available":    book.Stock - reserved[book.ID],
+		"ledger_total": ledgerTotal,
+	})
+}
+
+// listMovements: ตอบกลับประวัติตามตัวกรองแบบแบ่งหน้า (extra = Field เพิ่มเติมในคำตอบ)
+func (h *InventoryHandler) listMovements(c *fiber.Ctx, filter repository.StockMovementFilter, extra fiber.Map) error {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := clampLimit(c.QueryInt("limit", defaultPageLimit))
+
+	movements, total, err := h.store.StockMovements().List(c.UserContext(), filter, limit, (page-1)*limit)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if movements == nil {
+		movements = []models.StockMovement{}
+	}
+
+	resp := fiber.Map{
+		"data":  movements,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	return c.JSON(resp)
+}
+
+// orderMovementReason: เหตุผลของการเคลื่อนไหวที่มาจากคำสั่งซื้อ
+func orderMovementReason(orderID uint, status string) string {
+	return fmt.Sprintf("order #%d %s", orderID, status)
+}
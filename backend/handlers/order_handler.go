@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrderHandler: จัดการการสั่งซื้อ ประวัติคำสั่งซื้อ และการเปลี่ยนสถานะ
+type OrderHandler struct {
+	store          repository.Store
+	gateway        payments.Gateway
+	reservationTTL time.Duration
+}
+
+// NewOrderHandler: สร้าง OrderHandler (ใช้ gateway สำหรับคืนเงินเมื่อเปลี่ยนเป็น refunded)
+// Checkout จองสต็อกไว้ให้คำสั่งซื้อเป็นเวลา reservationTTL ก่อนถูกยกเลิกอัตโนมัติ
+func NewOrderHandler(store repository.Store, gateway payments.Gateway, reservationTTL time.Duration) *OrderHandler {
+	return &OrderHandler{store: store, gateway: gateway, reservationTTL: reservationTTL}
+}
+
+// errEmptyCart: ตะกร้าว่าง ไม่มีอะไรให้สั่งซื้อ
+var errEmptyCart = errors.New("cart is empty")
+
+// stockShortage: รายละเอียดของรายการในตะกร้าที่สต็อกไม่พอ
+type stockShortage struct {
+	CartItemID uint   `json:"cart_item_id"`
+	BookID     uint   `json:"book_id"`
+	Title      string `json:"title"`
+	Requested  int    `json:"requested"`
+	Available  int    `json:"available"`
+}
+
+// insufficientStockError: มีอย่างน้อยหนึ่งรายการที่สต็อกไม่พอ ทำให้ยกเลิกการสั่งซื้อทั้งหมด
+type insufficientStockError struct {
+	Lines []stockShortage
+}
+
+func (e *insufficientStockError) Error() string {
+	return "insufficient stock"
+}
+
+// illegalTransitionError: การเปลี่ยนสถานะคำสั่งซื้อที่ไม่อยู่ในตารางที่อนุญาต
+type illegalTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *illegalTransitionError) Error() string {
+	return "illegal order transition from " + e.From + " to " + e.To
+}
+
+// problem: Error ที่ส่งกลับให้ Client พร้อมสถานะที่เปลี่ยนไปได้จากสถานะปัจจุบัน (allowed)
+// ทุก Handler ใช้ตัวนี้ คำตอบของรหัส illegal_transition จึงมีรูปแบบเดียวกันเสมอ
+func (e *illegalTransitionError) problem() *apperr.Error {
+	allowed := models.AllowedTransitions(e.From)
+	if allowed == nil {
+		allowed = []string{}
+	}
+	return apperr.ErrIllegalTransition.
+		With("from", e.From).
+		With("to", e.To).
+		With("allowed", allowed)
+}
+
+// transitionOrder: เปลี่ยนสถานะคำสั่งซื้อภายใน Transaction ที่ส่งเข้ามา
+// ล็อกแถวคำสั่งซื้อ ตรวจสอบกับตารางสถานะ ปิดการจองหรือคืนสต็อกถ้าจำเป็น และบันทึกประวัติการเปลี่ยนสถานะ
+func transitionOrder(ctx context.Context, tx repository.Store, orderID uint, to string, actorID *uint, reason string) (*models.Order, error) {
+	// 1. ล็อกคำสั่งซื้อไว้ เพื่อไม่ให้มีการเปลี่ยนสถานะซ้อนกัน
+	order, err := tx.Orders().LockForUpdate(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. ตรวจสอบกับตารางการเปลี่ยนสถานะ
+	from := order.Status
+	if !models.CanTransition(from, to) {
+		return nil, &illegalTransitionError{From: from, To: to}
+	}
+
+	// 3. คำสั่งซื้อที่รอชำระเงินถือการจองสต็อกไว้: ชำระแล้วตัดสต็อกจริง ยกเลิกแล้วปล่อยการจอง
+	if from == models.OrderStatusPendingPayment {
+		if err := settleReservations(ctx, tx, order.ID, to == models.OrderStatusPaid, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	// คืนสต็อกกรณียกเลิก/คืนเงินหลังตัดสต็อกแล้วแต่ยังไม่ได้ส่งของ (บันทึกเป็นของคืนในสมุดบัญชีสต็อก)
+	if models.ReleasesStock(from, to) {
+		items, err := tx.Orders().Items(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if err := recordStockMovement(ctx, tx, &models.StockMovement{
+				BookID:   item.BookID,
+				Type:     models.MovementReturn,
+				Quantity: item.Quantity,
+				ActorID:  actorID,
+				OrderID:  &order.ID,
+				Reason:   orderMovementReason(order.ID, to),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 4. บันทึกสถานะใหม่และประวัติ
+	if err := tx.Orders().UpdateStatus(ctx, order.ID, to); err != nil {
+		return nil, err
+	}
+	if err := tx.Orders().AddTransition(ctx, &models.OrderTransition{
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}); err != nil {
+		return nil, err
+	}
+	order.Status = to
+	return order, nil
+}
+
+// Checkout: แปลงตะกร้าสินค้าของผู้ใช้ให้เป็นคำสั่งซื้อ (Order)
+// ทำทั้งหมดใน Transaction เดียว: ล็อกแถวหนังสือ จองสต็อก สร้าง Order และล้างตะกร้า
+// สต็อกถูกตัดจริงเมื่อชำระเงิน ถ้าไม่ชำระภายใน reservationTTL ตัวกวาดจะยกเลิกคำสั่งซื้อและปล่อยการจอง
+func (h *OrderHandler) Checkout(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	ctx := c.UserContext()
+	var order models.Order
+
+	err := h.store.Transaction(ctx, func(tx repository.Store) error {
+		// 1. ดึงรายการในตะกร้าของผู้ใช้
+		cartItems, err := tx.Carts().List(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(cartItems) == 0 {
+			return errEmptyCart
+		}
+
+		// 2. ล็อกแถวหนังสือ (SELECT ... FOR UPDATE) เรียงตาม ID เพื่อป้องกัน Deadlock
+		// ผู้ซื้อคนอื่นที่สั่งหนังสือเล่มเดียวกันจะต้องรอจนกว่า Transaction นี้จะจบ
+		bookIDs := make([]uint, 0, len(cartItems))
+		for _, item := range cartItems {
+			bookIDs = append(bookIDs, item.BookID)
+		}
+		books, err := tx.Books().LockForUpdate(ctx, bookIDs)
+		if err != nil {
+			return err
+		}
+		bookByID := make(map[uint]models.Book, len(books))
+		for _, b := range books {
+			bookByID[b.ID] = b
+		}
+		// ยอดที่คำสั่งซื้ออื่นจองไว้ (การจองของหนังสือเล่มหนึ่งถูกสร้างขณะถือล็อกของเล่มนั้นเสมอ จึงไม่เปลี่ยนระหว่างนี้)
+		reserved, err := tx.Reservations().Reserved(ctx, bookIDs)
+		if err != nil {
+			return err
+		}
+
+		// 3. ตรวจสอบสต็อกที่ขายได้ (สต็อกลบยอดจอง) ทุกรายการ ถ้ามีรายการไหนไม่พอให้ยกเลิกทั้งหมด
+		shortage := &insufficientStockError{}
+		for _, item := range cartItems {
+			book, found := bookByID[item.BookID]
+			available := book.Stock - reserved[item.BookID]
+			if !found || available < item.Quantity {
+				shortage.Lines = append(shortage.Lines, stockShortage{
+					CartItemID: item.ID,
+					BookID:     item.BookID,
+					Title:      book.Title,
+					Requested:  item.Quantity,
+					Available:  max(available, 0),
+				})
+			}
+		}
+		if len(shortage.Lines) > 0 {
+			return shortage
+		}
+
+		// 4. คัดลอกชื่อ/ราคาปัจจุบันลงในรายการสั่งซื้อ
+		order = models.Order{UserID: userID, Status: models.OrderStatusPendingPayment}
+		for _, item := range cartItems {
+			book := bookByID[item.BookID]
+			order.Items = append(order.Items, models.OrderItem{
+				BookID:   book.ID,
+				Title:    book.Title,
+				Price:    book.Price,
+				Quantity: item.Quantity,
+			})
+			order.Total += book.Price * item.Quantity
+		}
+
+		// 5. บันทึกคำสั่งซื้อ (พร้อมรายการสินค้าและประวัติสถานะแรก) จองสต็อก และล้างตะกร้า
+		order.Transitions = []models.OrderTransition{{
+			ToStatus: models.OrderStatusPendingPayment,
+			ActorID:  &userID,
+			Reason:   "checkout",
+		}}
+		if err := tx.Orders().Create(ctx, &order); err != nil {
+			return err
+		}
+		expiresAt := time.Now().Add(h.reservationTTL)
+		for _, item := range order.Items {
+			if err := tx.Reservations().Create(ctx, &models.StockReservation{
+				OrderID:   order.ID,
+				BookID:    item.BookID,
+				Quantity:  item.Quantity,
+				ExpiresAt: expiresAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return tx.Carts().Clear(ctx, userID)
+	})
+
+	if err != nil {
+		var shortage *insufficientStockError
+		switch {
+		case errors.Is(err, errEmptyCart):
+			return apperr.ErrCartEmpty
+		case errors.As(err, &shortage):
+			return apperr.ErrInsufficientStock.With("lines", shortage.Lines)
+		default:
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	return c.Status(201).JSON(order)
+}
+
+// GetOrders: ดึงประวัติคำสั่งซื้อของผู้ใช้ (เรียงจากใหม่ไปเก่า แบ่งหน้าด้วย ?page=&limit=)
+func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
+	userID := getUserID(c)
+
+	// 1. อ่านค่าการแบ่งหน้า พร้อมกำหนดขอบเขตที่อนุญาต
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 2. ดึงคำสั่งซื้อพร้อมรายการสินค้า (ชื่อและราคา ณ เวลาที่สั่งซื้อ) และจำนวนทั้งหมด
+	orders, total, err := h.store.Orders().ListByUser(c.UserContext(), userID, limit, (page-1)*limit)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if orders == nil {
+		orders = []models.Order{}
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  orders,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetOrder: ดึงรายละเอียดคำสั่งซื้อเดียว (เฉพาะของเจ้าของเท่านั้น)
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	orderID, err := c.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return apperr.ErrOrderNotFound
+	}
+
+	// ค้นหาโดยตรวจสอบ user_id ด้วย เพื่อไม่ให้เห็นคำสั่งซื้อของคนอื่น
+	order, err := h.store.Orders().GetForUser(c.UserContext(), userID, uint(orderID))
+	if err != nil {
+		return apperr.ErrOrderNotFound
+	}
+
+	return c.JSON(order)
+}
+
+// TransitionOrder: (Admin) เปลี่ยนสถานะคำสั่งซื้อตามตารางที่อนุญาต
+func (h *OrderHandler) TransitionOrder(c *fiber.Ctx) error {
+	actorID := getUserID(c)
+	ctx := c.UserContext()
+	orderID, err := c.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	type TransitionInput struct {
+		Status string `json:"status" validate:"required"`
+		Reason string `json:"reason" validate:"max=500"`
+	}
+	input := new(TransitionInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		order, err := transitionOrder(ctx, tx, uint(orderID), input.Status, &actorID, input.Reason)
+		if err != nil || input.Status != models.OrderStatusRefunded {
+			return err
+		}
+		// คืนเงินผ่านผู้ให้บริการ ถ้าล้มเหลว Transaction จะถูกยกเลิกและสถานะไม่เปลี่ยน
+		return refundOrderPayment(ctx, tx, h.gateway, order.ID)
+	})
+
+	if err != nil {
+		var illegal *illegalTransitionError
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return apperr.ErrOrderNotFound
+		case errors.As(err, &illegal):
+			return illegal.problem()
+		case errors.Is(err, errPaymentRefund):
+			return apperr.ErrRefundFailed.Wrap(err)
+		default:
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	// ส่งคำสั่งซื้อพร้อมรายการสินค้าและประวัติสถานะทั้งหมดกลับไป
+	order, err := h.store.Orders().Get(ctx, uint(orderID))
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	return c.JSON(order)
+}
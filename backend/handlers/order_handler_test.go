@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCheckoutReservesStock(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+
+	orderID := env.checkout(token, book.ID, 2)
+
+	// 1. คำสั่งซื้อรอชำระเงินพร้อมยอดรวมจากราคา ณ ตอนสั่ง
+	status, body := env.request("GET", fmt.Sprintf("/api/orders/%d", orderID), nil, authed(token))
+	wantStatus(t, "get order", status, http.StatusOK, body)
+	if body["status"] != models.OrderStatusPendingPayment || body["total"] != float64(600) {
+		t.Fatalf("order = %v, want pending_payment with total 600", body)
+	}
+
+	// 2. สต็อกยังไม่ถูกตัด แต่ที่ขายได้ลดลงตามยอดจอง และตะกร้าว่าง
+	if got := env.stock(book.ID); got != 5 {
+		t.Fatalf("stock = %d, want 5 (only reserved)", got)
+	}
+	status, body = env.request("GET", fmt.Sprintf("/books/%d", book.ID), nil, nil)
+	wantStatus(t, "get book", status, http.StatusOK, body)
+	if body["available"] != float64(3) {
+		t.Fatalf("available = %v, want 3", body["available"])
+	}
+	reservations, err := env.store.Reservations().ListByOrder(context.Background(), orderID)
+	if err != nil || len(reservations) != 1 || reservations[0].Quantity != 2 {
+		t.Fatalf("reservations = %+v (%v), want one of 2", reservations, err)
+	}
+	if items := env.cartItems(token); len(items) != 0 {
+		t.Fatalf("cart after checkout = %+v, want empty", items)
+	}
+
+	// 3. ตะกร้าว่างสั่งซื้อไม่ได้
+	status, body = env.request("POST", "/api/checkout", nil, authed(token))
+	wantStatus(t, "checkout empty cart", status, http.StatusBadRequest, body)
+	wantCode(t, "checkout empty cart", body, "cart_empty")
+}
+
+func TestCheckoutRejectsStockReservedByOthers(t *testing.T) {
+	env := newTestEnv(t)
+	_, first := env.createUser("first@example.com")
+	_, second := env.createUser("second@example.com")
+	book := env.createBook("Go in Action", 300, 3)
+
+	// ทั้งสองคนใส่ตะกร้าได้ (ยังไม่มีใครจอง) แต่คนแรกสั่งซื้อก่อน
+	status, body := env.request("POST", "/api/cart", fiber.Map{"book_id": book.ID, "quantity": 3}, authed(second))
+	wantStatus(t, "second adds to cart", status, http.StatusOK, body)
+	env.checkout(first, book.ID, 3)
+
+	status, body = env.request("POST", "/api/checkout", nil, authed(second))
+	wantStatus(t, "second checkout", status, http.StatusConflict, body)
+	wantCode(t, "second checkout", body, "insufficient_stock")
+	lines, _ := body["lines"].([]interface{})
+	if len(lines) != 1 || lines[0].(map[string]interface{})["available"] != float64(0) {
+		t.Fatalf("lines = %v, want one line with available 0", body["lines"])
+	}
+	if items := env.cartItems(second); len(items) != 1 {
+		t.Fatalf("cart after failed checkout = %+v, want it kept", items)
+	}
+}
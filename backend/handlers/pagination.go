@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ขอบเขตของจำนวนรายการต่อหน้า
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// ทิศทางของ Cursor
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor: ตำแหน่งของรายการสุดท้าย (หรือแรก) ที่เห็นแล้ว สำหรับการแบ่งหน้าแบบ Keyset
+// เก็บคีย์การเรียงไว้ด้วย เพื่อไม่ให้ใช้ Cursor ข้ามการเรียงคนละแบบ
+type pageCursor struct {
+	Sort      string `json:"s"`
+	Order     string `json:"o"`
+	Value     string `json:"v"`
+	ID        uint   `json:"id"`
+	Direction string `json:"d"`
+}
+
+// encodeCursor: แปลง Cursor เป็นข้อความทึบ (Base64 URL-safe) สำหรับส่งให้ Client
+func encodeCursor(cur pageCursor) string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor: แปลงข้อความจาก Client กลับเป็น Cursor
+func decodeCursor(s string) (pageCursor, error) {
+	var cur pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, errInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return cur, errInvalidCursor
+	}
+	if cur.Direction != cursorNext && cur.Direction != cursorPrev {
+		return cur, errInvalidCursor
+	}
+	return cur, nil
+}
+
+// clampLimit: บังคับให้จำนวนรายการต่อหน้าอยู่ในช่วงที่อนุญาต
+func clampLimit(limit int) int {
+	if limit < 1 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+// cursorValue: แปลงค่าใน Cursor กลับเป็นชนิดข้อมูลของคอลัมน์ที่ใช้เรียง
+func cursorValue(sort, value string) (interface{}, error) {
+	switch sort {
+	case "price":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		return v, nil
+	case "created_at":
+		v, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
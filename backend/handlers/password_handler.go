@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/mail"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// อายุของลิงก์รีเซ็ตรหัสผ่าน
+const passwordResetTTL = time.Hour
+
+// errInvalidResetToken: Token รีเซ็ตรหัสผ่านไม่มีอยู่ หมดอายุ หรือถูกใช้ไปแล้ว
+var errInvalidResetToken = errors.New("invalid password reset token")
+
+// PasswordHandler: จัดการการลืมรหัสผ่านและการตั้งรหัสผ่านใหม่ผ่านลิงก์ทางอีเมล
+type PasswordHandler struct {
+	store    repository.Store
+	mailer   mail.Mailer
+	resetURL string // หน้าเว็บที่รับ ?token= แล้วเรียก POST /password/reset
+}
+
+// NewPasswordHandler: สร้าง PasswordHandler (frontendURL ใช้สร้างลิงก์ในอีเมล)
+func NewPasswordHandler(store repository.Store, mailer mail.Mailer, frontendURL string) *PasswordHandler {
+	return &PasswordHandler{store: store, mailer: mailer, resetURL: frontendURL + "/reset-password"}
+}
+
+// ForgotPassword: ส่งลิงก์รีเซ็ตรหัสผ่านไปที่อีเมล
+// ตอบเหมือนกันทุกกรณี ไม่บอกว่าอีเมลนี้มีในระบบหรือไม่
+func (h *PasswordHandler) ForgotPassword(c *fiber.Ctx) error {
+	type ForgotInput struct {
+		Email string `json:"email" validate:"required,email,max=255"`
+	}
+	input := new(ForgotInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	// ค้นหาและส่งอีเมลเบื้องหลัง เพื่อให้เวลาตอบกลับไม่ต่างกันระหว่างอีเมลที่มีและไม่มีในระบบ
+	go h.sendResetLink(input.Email, i18n.From(c))
+
+	return c.Status(202).JSON(fiber.Map{
+		"message": i18n.T(i18n.From(c), "message.password_reset_requested"),
+	})
+}
+
+// sendResetLink: ส่งลิงก์รีเซ็ตรหัสผ่านให้เจ้าของอีเมล (ถ้ามีในระบบ) ทำงานเบื้องหลัง จึงเขียน Error ลง Log เท่านั้น
+// อีเมลใช้ภาษาที่ผู้ใช้ตั้งไว้ ถ้าไม่ได้ตั้งใช้ภาษาของ Request ที่ขอ (lang)
+func (h *PasswordHandler) sendResetLink(email string, lang i18n.Language) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	user, err := h.store.Users().GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("password reset: ค้นหาผู้ใช้ไม่สำเร็จ: %v", err)
+		}
+		return
+	}
+
+	if err := issueResetLink(ctx, h.store, h.mailer, h.resetURL, user, lang, "mail.reset_password"); err != nil {
+		log.Printf("password reset: %v", err)
+	}
+}
+
+// issueResetLink: ออก Token ใหม่ (ยกเลิก Token เก่าที่ยังไม่ใช้) และส่งลิงก์ทางอีเมล
+// template คือ Key ของอีเมลในแคตตาล็อก (ใช้ template+".subject" และ template+".body")
+func issueResetLink(ctx context.Context, store repository.Store, mailer mail.Mailer, resetURL string, user *models.User, lang i18n.Language, template string) error {
+	raw, err := randomToken()
+	if err != nil {
+		return fmt.Errorf("สร้าง Token ไม่สำเร็จ: %w", err)
+	}
+	now := time.Now()
+	err = store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.PasswordResets().InvalidateUser(ctx, user.ID, now); err != nil {
+			return err
+		}
+		return tx.PasswordResets().Create(ctx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashToken(raw),
+			ExpiresAt: now.Add(passwordResetTTL),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("บันทึก Token ไม่สำเร็จ: %w", err)
+	}
+
+	link := resetURL + "?token=" + url.QueryEscape(raw)
+	lang = i18n.Preferred(user.Language, lang)
+	if err := mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, template+".subject"),
+		Body:    i18n.T(lang, template+".body", user.Name, link),
+	}); err != nil {
+		return fmt.Errorf("ส่งอีเมลไม่สำเร็จ: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword: ตั้งรหัสผ่านใหม่ด้วย Token จากอีเมล แล้วออกจากระบบทุกเครื่อง
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. รับ Token และรหัสผ่านใหม่
+	type ResetInput struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required,min=8,max=72"` // bcrypt ใช้ได้ไม่เกิน 72 ไบต์
+	}
+	input := new(ResetInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	// 2. เข้ารหัสรหัสผ่านใหม่ก่อนเปิด Transaction (bcrypt ใช้เวลานาน ไม่ควรถือล็อกไว้ระหว่างนั้น)
+	hashedPassword, err := hashPassword(input.Password)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 3. ล็อก Token ตรวจว่ายังใช้ได้ เปลี่ยนรหัสผ่าน และปิด Token ทั้งหมดของผู้ใช้
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		token, err := tx.PasswordResets().GetByHashForUpdate(ctx, hashToken(input.Token))
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+		if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+			return errInvalidResetToken
+		}
+
+		// 4. เปลี่ยนรหัสผ่านและออกจากระบบทุกเครื่อง เผื่อรหัสผ่านเดิมรั่วไหล
+		return ReplacePassword(ctx, tx, token.UserID, hashedPassword)
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidResetToken) {
+			return apperr.ErrInvalidResetToken
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.password_reset_success")})
+}
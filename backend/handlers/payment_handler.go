@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// สกุลเงินที่ใช้กับทุกคำสั่งซื้อ
+const paymentCurrency = "THB"
+
+// paymentStatusNeedsReconciliation: ลูกค้าถูกตัดเงินแล้วแต่คำสั่งซื้อรับไม่ได้ และคืนเงินอัตโนมัติไม่สำเร็จ (ผู้ดูแลต้องคืนเงินเอง)
+const paymentStatusNeedsReconciliation = "needs_reconciliation"
+
+// PaymentHandler: จัดการการชำระเงินผ่านผู้ให้บริการ, Webhook และ PromptPay
+type PaymentHandler struct {
+	store       repository.Store
+	gateway     payments.Gateway
+	promptPayID string // หมายเลข PromptPay ของร้าน
+}
+
+// NewPaymentHandler: สร้าง PaymentHandler
+func NewPaymentHandler(store repository.Store, gateway payments.Gateway, promptPayID string) *PaymentHandler {
+	return &PaymentHandler{store: store, gateway: gateway, promptPayID: promptPayID}
+}
+
+// errPaymentRefund: ผู้ให้บริการปฏิเสธหรือไม่ตอบสนองการคืนเงิน
+var errPaymentRefund = errors.New("payment refund failed")
+
+// refundOrderPayment: คืนเงินของการชำระที่สำเร็จแล้วของคำสั่งซื้อ (ถ้ามี)
+func refundOrderPayment(ctx context.Context, tx repository.Store, gateway payments.Gateway, orderID uint) error {
+	payment, err := tx.Payments().FindByOrderStatus(ctx, orderID, payments.IntentStatusSucceeded)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// การชำระผ่านผู้ให้บริการอื่น (เช่น PromptPay) ต้องโอนคืนเอง ระบบบันทึกสถานะอย่างเดียว
+	if payment.Provider == gateway.Name() {
+		if _, err := gateway.Refund(ctx, payment.IntentID, payment.Amount); err != nil {
+			log.Printf("payments: refund of intent %s failed: %v", payment.IntentID, err)
+			return errPaymentRefund
+		}
+	}
+	return tx.Payments().UpdateStatus(ctx, payment.ID, payments.IntentStatusRefunded)
+}
+
+// refundUnmatchedPayment: คืนเงิน amount ที่ลูกค้าถูกตัดไปแต่คำสั่งซื้อรับไม่ได้ (ใช้ภายใน Transaction ของ Webhook)
+// เช่น คำสั่งซื้อถูกยกเลิกไปแล้ว หรือยอดที่ตัดไม่ตรงกับยอดของคำสั่งซื้อ
+// ถ้าผู้ให้บริการคืนเงินไม่สำเร็จ ให้ทำเครื่องหมาย needs_reconciliation ไว้ตรวจสอบและคืนเงินเองภายหลัง
+func refundUnmatchedPayment(ctx context.Context, tx repository.Store, gateway payments.Gateway, payment *models.Payment, amount int) error {
+	if payment.Provider == gateway.Name() {
+		_, err := gateway.Refund(ctx, payment.IntentID, amount)
+		if err == nil {
+			return tx.Payments().UpdateStatus(ctx, payment.ID, payments.IntentStatusRefunded)
+		}
+		log.Printf("payments: refund of unmatched intent %s failed: %v", payment.IntentID, err)
+	}
+	return tx.Payments().UpdateStatus(ctx, payment.ID, paymentStatusNeedsReconciliation)
+}
+
+// pendingOrder: ดึงคำสั่งซื้อของผู้ใช้ที่ยังรอชำระเงินอยู่ หรือคืน Error ที่ส่งกลับให้ Client ได้
+func (h *PaymentHandler) pendingOrder(c *fiber.Ctx) (*models.Order, error) {
+	orderID, err := c.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return nil, apperr.ErrOrderNotFound
+	}
+	order, err := h.store.Orders().GetForUser(c.UserContext(), getUserID(c), uint(orderID))
+	if err != nil {
+		return nil, apperr.ErrOrderNotFound
+	}
+	if order.Status != models.OrderStatusPendingPayment {
+		return nil, apperr.ErrOrderNotPending.With("status", order.Status)
+	}
+	return order, nil
+}
+
+// PayOrder: สร้างรายการรอชำระเงิน (Payment Intent) สำหรับคำสั่งซื้อของผู้ใช้
+// ถ้าคำสั่งซื้อมี Intent ที่ยังรอชำระอยู่แล้วให้คืนอันเดิม (200) ไม่สร้างใหม่ ลูกค้าจึงไม่ถูกตัดเงินซ้ำ
+func (h *PaymentHandler) PayOrder(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. ตรวจสอบว่าเป็นคำสั่งซื้อของผู้ใช้คนนี้และยังรอชำระเงินอยู่
+	order, err := h.pendingOrder(c)
+	if err != nil {
+		return err
+	}
+
+	var payment *models.Payment
+	created := false
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		// 2. ล็อกคำสั่งซื้อไว้ Request ที่กดจ่ายพร้อมกันจะรอแล้วเห็น Intent ที่อันแรกสร้าง
+		// แล้วตรวจสถานะซ้ำจากแถวที่ล็อกแล้ว (อาจถูกยกเลิกหรือการจองหมดอายุไปหลังข้อ 1)
+		locked, err := tx.Orders().LockForUpdate(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != models.OrderStatusPendingPayment {
+			return &illegalTransitionError{From: locked.Status, To: models.OrderStatusPaid}
+		}
+		existing, err := tx.Payments().FindByOrderStatus(ctx, order.ID, payments.IntentStatusPending)
+		if err == nil && existing.Provider == h.gateway.Name() {
+			payment = existing
+			return nil
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		// 3. ขอ Intent จากผู้ให้บริการ
+		intent, err := h.gateway.CreateIntent(ctx, order.ID, order.Total, paymentCurrency)
+		if err != nil {
+			return &gatewayError{err}
+		}
+
+		// 4. บันทึกไว้เพื่อจับคู่กับ Webhook ภายหลัง
+		payment = &models.Payment{
+			OrderID:  order.ID,
+			Provider: h.gateway.Name(),
+			IntentID: intent.ID,
+			Amount:   intent.Amount,
+			Currency: intent.Currency,
+			Status:   intent.Status,
+		}
+		created = true
+		return tx.Payments().Create(ctx, payment)
+	})
+	if err != nil {
+		var gwErr *gatewayError
+		var illegal *illegalTransitionError
+		switch {
+		case errors.As(err, &gwErr):
+			return apperr.ErrPaymentGateway.Wrap(gwErr.err)
+		case errors.As(err, &illegal):
+			return illegal.problem()
+		default:
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	if created {
+		return c.Status(fiber.StatusCreated).JSON(payment)
+	}
+	return c.JSON(payment)
+}
+
+// gatewayError: ผู้ให้บริการรับชำระเงินตอบ Error (แยกจาก Error ของฐานข้อมูลใน Transaction เดียวกัน)
+type gatewayError struct {
+	err error
+}
+
+func (e *gatewayError) Error() string { return "payment gateway: " + e.err.Error() }
+
+func (e *gatewayError) Unwrap() error { return e.err }
+
+// PaymentWebhook: รับผลการชำระเงินจากผู้ให้บริการ (ตรวจลายเซ็นทุกครั้ง)
+// Webhook ที่มี Event ID ซ้ำจะถูกข้าม เพื่อไม่ให้ประมวลผลซ้ำ
+func (h *PaymentHandler) PaymentWebhook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. ตรวจลายเซ็นและแปลงเป็น Event
+	event, err := h.gateway.VerifyWebhook(c.Body(), c.Get(payments.SignatureHeader))
+	if err != nil {
+		return apperr.ErrInvalidWebhookSignature
+	}
+
+	duplicate := false
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		// 2. บันทึก Event ID ก่อน ถ้ามีอยู่แล้วแปลว่าเป็น Webhook ซ้ำ
+		recorded, err := tx.Payments().RecordEvent(ctx, &models.PaymentEvent{
+			EventID:  event.ID,
+			Type:     event.Type,
+			IntentID: event.IntentID,
+		})
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			duplicate = true
+			return nil
+		}
+
+		// 3. หา Payment ที่ตรงกับ Intent
+		payment, err := tx.Payments().GetByIntentForUpdate(ctx, event.IntentID)
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case payments.EventPaymentSucceeded:
+			if payment.Status == payments.IntentStatusSucceeded {
+				return nil // Intent นี้ถูกประมวลผลไปแล้วจาก Event อื่น
+			}
+			if event.Amount != payment.Amount {
+				// ลูกค้าถูกตัดเงินไม่ตรงกับยอดของคำสั่งซื้อ ไม่เลื่อนคำสั่งซื้อ และคืนยอดที่ตัดไป
+				log.Printf("payments: amount mismatch for intent %s: got %d, want %d", event.IntentID, event.Amount, payment.Amount)
+				return refundUnmatchedPayment(ctx, tx, h.gateway, payment, event.Amount)
+			}
+			if err := tx.Payments().UpdateStatus(ctx, payment.ID, payments.IntentStatusSucceeded); err != nil {
+				return err
+			}
+			// 4. เลื่อนคำสั่งซื้อเป็น "ชำระแล้ว" (ระบบเป็นผู้เปลี่ยน จึงไม่มี Actor)
+			_, err := transitionOrder(ctx, tx, payment.OrderID, models.OrderStatusPaid, nil, "payment "+payment.IntentID+" succeeded")
+			var illegal *illegalTransitionError
+			if errors.As(err, &illegal) {
+				// คำสั่งซื้อถูกยกเลิก (เช่น การจองหมดอายุ) หรือชำระไปแล้ว ลูกค้าถูกตัดเงินโดยไม่ได้ของ ต้องคืนเงิน
+				log.Printf("payments: order %d not moved to paid: %v", payment.OrderID, err)
+				return refundUnmatchedPayment(ctx, tx, h.gateway, payment, payment.Amount)
+			}
+			return err
+		case payments.EventPaymentFailed:
+			return tx.Payments().UpdateStatus(ctx, payment.ID, "failed")
+		case payments.EventPaymentRefunded:
+			return tx.Payments().UpdateStatus(ctx, payment.ID, payments.IntentStatusRefunded)
+		}
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrPaymentNotFound
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{"received": true, "duplicate": duplicate})
+}
+
+// FakeCapturePayment: (เฉพาะผู้ให้บริการจำลอง) จำลองว่าลูกค้าชำระเงินสำเร็จ
+// คืน Payload และลายเซ็นของ Webhook เพื่อนำไปส่งที่ /payments/webhook
+func (h *PaymentHandler) FakeCapturePayment(c *fiber.Ctx) error {
+	fake, ok := h.gateway.(*payments.Fake)
+	if !ok {
+		return apperr.ErrNotFound
+	}
+
+	intentID := c.Params("id")
+	if _, err := fake.Capture(c.UserContext(), intentID); err != nil {
+		return apperr.ErrCaptureFailed.With("reason", err.Error())
+	}
+	payload, signature, err := fake.SignedEvent(payments.EventPaymentSucceeded, intentID)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"payload":   string(payload),
+		"signature": signature,
+		"header":    payments.SignatureHeader,
+	})
+}
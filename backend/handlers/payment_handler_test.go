@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+)
+
+// payOrder: สร้าง Payment Intent ของคำสั่งซื้อ คืน Intent ID
+func (e *testEnv) payOrder(token string, orderID uint) string {
+	e.t.Helper()
+	status, body := e.request("POST", fmt.Sprintf("/api/orders/%d/pay", orderID), nil, authed(token))
+	wantStatus(e.t, "pay order", status, http.StatusCreated, body)
+	return body["intent_id"].(string)
+}
+
+// captureWebhook: ให้ Gateway จำลองตัดเงินแล้วส่ง Webhook ที่เซ็นแล้วเข้าแอป
+func (e *testEnv) captureWebhook(intentID string) (int, map[string]interface{}) {
+	e.t.Helper()
+	if _, err := e.gateway.Capture(context.Background(), intentID); err != nil {
+		e.t.Fatal(err)
+	}
+	payload, signature, err := e.gateway.SignedEvent(payments.EventPaymentSucceeded, intentID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return e.request("POST", "/payments/webhook", payload, map[string]string{payments.SignatureHeader: signature})
+}
+
+func TestPaymentWebhookMarksOrderPaid(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 2)
+
+	// 1. กดจ่ายซ้ำได้ Intent เดิม ไม่สร้างใหม่
+	intentID := env.payOrder(token, orderID)
+	status, body := env.request("POST", fmt.Sprintf("/api/orders/%d/pay", orderID), nil, authed(token))
+	wantStatus(t, "pay again", status, http.StatusOK, body)
+	if body["intent_id"] != intentID {
+		t.Fatalf("second pay returned intent %v, want %s", body["intent_id"], intentID)
+	}
+
+	// 2. Webhook ยืนยันการชำระ: คำสั่งซื้อเป็น paid สต็อกถูกตัดจริงและบันทึกเป็นการขาย
+	status, body = env.captureWebhook(intentID)
+	wantStatus(t, "webhook", status, http.StatusOK, body)
+	if body["duplicate"] != false {
+		t.Fatalf("webhook = %v, want duplicate false", body)
+	}
+	if got := env.orderStatus(orderID); got != models.OrderStatusPaid {
+		t.Fatalf("order status = %s, want paid", got)
+	}
+	if got := env.stock(book.ID); got != 3 {
+		t.Fatalf("stock = %d, want 3", got)
+	}
+	movements, _, err := env.store.StockMovements().List(context.Background(),
+		repository.StockMovementFilter{BookID: book.ID, Type: models.MovementSale}, 10, 0)
+	if err != nil || len(movements) != 1 || movements[0].Quantity != -2 || *movements[0].OrderID != orderID {
+		t.Fatalf("sale movements = %+v (%v), want one of -2 for the order", movements, err)
+	}
+
+	// 3. Webhook เดิมซ้ำไม่มีผล
+	payload, signature, err := env.gateway.SignedEvent(payments.EventPaymentSucceeded, intentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, body = env.request("POST", "/payments/webhook", payload, map[string]string{payments.SignatureHeader: signature})
+	wantStatus(t, "replayed webhook", status, http.StatusOK, body)
+	if got := env.stock(book.ID); got != 3 {
+		t.Fatalf("stock after replay = %d, want 3", got)
+	}
+
+	// 4. คำสั่งซื้อที่ชำระแล้วสร้าง Intent ใหม่ไม่ได้
+	status, body = env.request("POST", fmt.Sprintf("/api/orders/%d/pay", orderID), nil, authed(token))
+	wantStatus(t, "pay paid order", status, http.StatusConflict, body)
+}
+
+func TestPaymentWebhookRejectsBadSignature(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 1)
+	intentID := env.payOrder(token, orderID)
+
+	payload, _, err := env.gateway.SignedEvent(payments.EventPaymentSucceeded, intentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, body := env.request("POST", "/payments/webhook", payload, map[string]string{payments.SignatureHeader: "forged"})
+	wantStatus(t, "forged webhook", status, http.StatusUnauthorized, body)
+	wantCode(t, "forged webhook", body, "invalid_webhook_signature")
+	if got := env.orderStatus(orderID); got != models.OrderStatusPendingPayment {
+		t.Fatalf("order status = %s, want pending_payment", got)
+	}
+}
+
+func TestPaymentForCancelledOrderIsRefunded(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 2)
+	intentID := env.payOrder(token, orderID)
+
+	// การจองหมดอายุและตัวกวาดยกเลิกคำสั่งซื้อก่อนเงินเข้า
+	ctx := context.Background()
+	err := env.store.Transaction(ctx, func(tx repository.Store) error {
+		_, err := transitionOrder(ctx, tx, orderID, models.OrderStatusCancelled, nil, "stock reservation expired")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := env.captureWebhook(intentID)
+	wantStatus(t, "late webhook", status, http.StatusOK, body)
+	if got := env.orderStatus(orderID); got != models.OrderStatusCancelled {
+		t.Fatalf("order status = %s, want cancelled", got)
+	}
+	if got := env.stock(book.ID); got != 5 {
+		t.Fatalf("stock = %d, want 5", got)
+	}
+	payment, err := env.store.Payments().GetByIntentForUpdate(ctx, intentID)
+	if err != nil || payment.Status != payments.IntentStatusRefunded {
+		t.Fatalf("payment = %+v (%v), want refunded", payment, err)
+	}
+}
+
+func TestPaymentWithWrongAmountIsRefunded(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 2)
+	intentID := env.payOrder(token, orderID)
+	ctx := context.Background()
+
+	// ผู้ให้บริการแจ้งว่าตัดเงินสำเร็จแต่ยอดไม่ตรงกับคำสั่งซื้อ (600)
+	if _, err := env.gateway.Capture(ctx, intentID); err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(payments.Event{
+		ID: "evt_wrong_amount", Type: payments.EventPaymentSucceeded, IntentID: intentID, Amount: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, body := env.request("POST", "/payments/webhook", payload, map[string]string{payments.SignatureHeader: env.gateway.Sign(payload)})
+	wantStatus(t, "webhook with wrong amount", status, http.StatusOK, body)
+
+	// คำสั่งซื้อยังรอชำระ สต็อกไม่ถูกตัด และยอดที่ตัดไปถูกคืนผ่าน Gateway
+	if got := env.orderStatus(orderID); got != models.OrderStatusPendingPayment {
+		t.Fatalf("order status = %s, want pending_payment", got)
+	}
+	if got := env.stock(book.ID); got != 5 {
+		t.Fatalf("stock = %d, want 5", got)
+	}
+	payment, err := env.store.Payments().GetByIntentForUpdate(ctx, intentID)
+	if err != nil || payment.Status != payments.IntentStatusRefunded {
+		t.Fatalf("payment = %+v (%v), want refunded", payment, err)
+	}
+}
+
+// cancelBeforeLockStore: ที่เก็บข้อมูลที่ยกเลิกคำสั่งซื้อ (แบบตัวกวาดการจองที่หมดอายุ) ก่อนล็อกแถวครั้งถัดไป
+// จำลองคำสั่งซื้อที่ถูกยกเลิกระหว่างที่ PayOrder ตรวจสถานะแล้วแต่ยังไม่ได้ล็อก
+type cancelBeforeLockStore struct {
+	repository.Store
+	armed *bool
+}
+
+func (s cancelBeforeLockStore) Transaction(ctx context.Context, fn func(tx repository.Store) error) error {
+	return s.Store.Transaction(ctx, func(tx repository.Store) error {
+		return fn(cancelBeforeLockStore{Store: tx, armed: s.armed})
+	})
+}
+
+func (s cancelBeforeLockStore) Orders() repository.OrderRepository {
+	return cancelBeforeLockOrders{OrderRepository: s.Store.Orders(), tx: s.Store, armed: s.armed}
+}
+
+type cancelBeforeLockOrders struct {
+	repository.OrderRepository
+	tx    repository.Store
+	armed *bool
+}
+
+func (o cancelBeforeLockOrders) LockForUpdate(ctx context.Context, id uint) (*models.Order, error) {
+	if *o.armed {
+		*o.armed = false
+		if _, err := transitionOrder(ctx, o.tx, id, models.OrderStatusCancelled, nil, "stock reservation expired"); err != nil {
+			return nil, err
+		}
+	}
+	return o.OrderRepository.LockForUpdate(ctx, id)
+}
+
+func TestPayOrderRechecksStatusAfterLock(t *testing.T) {
+	armed := false
+	env := newTestEnvWith(t, func(memory *repository.MemoryStore) repository.Store {
+		return cancelBeforeLockStore{Store: memory, armed: &armed}
+	})
+	_, token := env.createUser("ann@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 2)
+
+	// คำสั่งซื้อยังรอชำระตอนตรวจครั้งแรก แต่ถูกยกเลิกก่อนล็อกแถว: ต้องไม่สร้าง Intent
+	armed = true
+	status, body := env.request("POST", fmt.Sprintf("/api/orders/%d/pay", orderID), nil, authed(token))
+	wantStatus(t, "pay order cancelled before lock", status, http.StatusConflict, body)
+	wantCode(t, "pay order cancelled before lock", body, "illegal_transition")
+	if body["from"] != models.OrderStatusCancelled {
+		t.Fatalf("from = %v, want cancelled", body["from"])
+	}
+	if _, err := env.store.Payments().FindByOrderStatus(context.Background(), orderID, payments.IntentStatusPending); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("pending payment lookup = %v, want ErrNotFound (no intent created)", err)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/skip2/go-qrcode"
+)
+
+// ขนาดภาพ QR Code (พิกเซล)
+const promptPayQRSize = 512
+
+// GetPromptPayQR: สร้าง QR Code PromptPay (PNG) ตามยอดของคำสั่งซื้อที่รอชำระเงิน
+// Payload ดิบจะถูกส่งกลับใน Header X-PromptPay-Payload ด้วย
+func (h *PaymentHandler) GetPromptPayQR(c *fiber.Ctx) error {
+	// 1. ตรวจสอบว่าเป็นคำสั่งซื้อของผู้ใช้และยังรอชำระเงินอยู่
+	order, err := h.pendingOrder(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. สร้าง Payload ด้วยหมายเลข PromptPay ของร้าน
+	payload, err := payments.PromptPayPayload(h.promptPayID, order.Total)
+	if err != nil {
+		return apperr.ErrPromptPayNotConfigured
+	}
+
+	// 3. แปลงเป็นภาพ QR Code
+	png, err := qrcode.Encode(payload, qrcode.Medium, promptPayQRSize)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	c.Set("X-PromptPay-Payload", payload)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Type("png")
+	return c.Send(png)
+}
+
+// ConfirmPromptPayPayment: (Admin) ยืนยันว่าได้รับเงินโอน PromptPay แล้วหลังตรวจสอบสลิป
+// เลขอ้างอิงของสลิปใช้ซ้ำไม่ได้ เพื่อกันการนำสลิปเดิมมายืนยันหลายคำสั่งซื้อ
+func (h *PaymentHandler) ConfirmPromptPayPayment(c *fiber.Ctx) error {
+	actorID := getUserID(c)
+	ctx := c.UserContext()
+	orderID, err := c.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return apperr.ErrInvalidID
+	}
+
+	type ConfirmInput struct {
+		Reference string `json:"reference" validate:"required,max=100"` // เลขอ้างอิงรายการโอนจากสลิป
+		Note      string `json:"note" validate:"max=500"`
+	}
+	input := new(ConfirmInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	var order *models.Order
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		// 1. ตรวจสอบว่าเลขอ้างอิงนี้ยังไม่เคยถูกใช้
+		used, err := tx.Payments().IntentExists(ctx, input.Reference)
+		if err != nil {
+			return err
+		}
+		if used {
+			return errSlipAlreadyUsed
+		}
+
+		// 2. เลื่อนคำสั่งซื้อเป็น "ชำระแล้ว" โดยระบุแอดมินผู้ยืนยัน
+		reason := "PromptPay slip " + input.Reference
+		if input.Note != "" {
+			reason += ": " + input.Note
+		}
+		order, err = transitionOrder(ctx, tx, uint(orderID), models.OrderStatusPaid, &actorID, reason)
+		if err != nil {
+			return err
+		}
+
+		// 3. บันทึกการชำระเงินไว้คู่กับคำสั่งซื้อ
+		return tx.Payments().Create(ctx, &models.Payment{
+			OrderID:  order.ID,
+			Provider: payments.ProviderPromptPay,
+			IntentID: input.Reference,
+			Amount:   order.Total,
+			Currency: paymentCurrency,
+			Status:   payments.IntentStatusSucceeded,
+		})
+	})
+
+	if err != nil {
+		var illegal *illegalTransitionError
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return apperr.ErrOrderNotFound
+		case errors.Is(err, errSlipAlreadyUsed):
+			return apperr.ErrSlipAlreadyUsed
+		case errors.As(err, &illegal):
+			return illegal.problem()
+		default:
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  i18n.T(i18n.From(c), "message.payment_confirmed"),
+		"order_id": order.ID,
+		"status":   models.OrderStatusPaid,
+	})
+}
+
+// errSlipAlreadyUsed: เลขอ้างอิงสลิปนี้ถูกใช้ยืนยันการชำระเงินไปแล้ว
+var errSlipAlreadyUsed = errors.New("promptpay slip reference already used")
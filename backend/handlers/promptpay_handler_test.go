@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"my-fiber-app/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConfirmPromptPayOnPaidOrderListsAllowedTransitions(t *testing.T) {
+	env := newTestEnv(t)
+	_, token := env.createUser("ann@example.com")
+	_, admin := env.createUser("admin@example.com")
+	book := env.createBook("Go in Action", 300, 5)
+	orderID := env.checkout(token, book.ID, 1)
+	path := fmt.Sprintf("/admin/orders/%d/promptpay/confirm", orderID)
+
+	status, body := env.request("POST", path, fiber.Map{"reference": "SLIP-1"}, authed(admin))
+	wantStatus(t, "confirm slip", status, http.StatusOK, body)
+
+	// ยืนยันซ้ำด้วยสลิปใหม่: ได้ illegal_transition รูปแบบเดียวกับ TransitionOrder (มี allowed)
+	status, body = env.request("POST", path, fiber.Map{"reference": "SLIP-2"}, authed(admin))
+	wantStatus(t, "confirm paid order", status, http.StatusConflict, body)
+	wantCode(t, "confirm paid order", body, "illegal_transition")
+	allowed, ok := body["allowed"].([]interface{})
+	if !ok || len(allowed) != len(models.AllowedTransitions(models.OrderStatusPaid)) {
+		t.Fatalf("allowed = %v, want %v", body["allowed"], models.AllowedTransitions(models.OrderStatusPaid))
+	}
+	if body["from"] != models.OrderStatusPaid || body["to"] != models.OrderStatusPaid {
+		t.Fatalf("from/to = %v/%v, want paid/paid", body["from"], body["to"])
+	}
+}
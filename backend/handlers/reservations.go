@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+)
+
+// DefaultReservationTTL: เวลาที่ Checkout จองสต็อกไว้ให้ชำระเงิน (ตั้งใหม่ได้ด้วย RESERVATION_TTL_MINUTES)
+const DefaultReservationTTL = 15 * time.Minute
+
+// reservationSweepBatch: จำนวนคำสั่งซื้อที่หมดอายุสูงสุดที่ยกเลิกในการกวาดหนึ่งรอบ
+const reservationSweepBatch = 100
+
+// availableStock: จำนวนที่ขายได้จริงของหนังสือ (สต็อกลบยอดที่ถูกจองไว้ให้คำสั่งซื้อที่รอชำระเงิน)
+func availableStock(ctx context.Context, reservations repository.ReservationRepository, book *models.Book) (int, error) {
+	reserved, err := reservations.Reserved(ctx, []uint{book.ID})
+	if err != nil {
+		return 0, err
+	}
+	return book.Stock - reserved[book.ID], nil
+}
+
+// settleReservations: ปิดการจองของคำสั่งซื้อที่กำลังออกจากสถานะรอชำระเงิน (ใช้ภายใน Transaction)
+// commit = true (ชำระเงินแล้ว) ตัดสต็อกจริงตามยอดจองเป็นการขายในสมุดบัญชีสต็อก ไม่อย่างนั้นแค่ปล่อยการจองให้คนอื่นซื้อต่อได้
+func settleReservations(ctx context.Context, tx repository.Store, orderID uint, commit bool, actorID *uint) error {
+	if commit {
+		reservations, err := tx.Reservations().ListByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, res := range reservations {
+			if err := recordStockMovement(ctx, tx, &models.StockMovement{
+				BookID:   res.BookID,
+				Type:     models.MovementSale,
+				Quantity: -res.Quantity,
+				ActorID:  actorID,
+				OrderID:  &orderID,
+				Reason:   orderMovementReason(orderID, models.OrderStatusPaid),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Reservations().DeleteByOrder(ctx, orderID)
+}
+
+// ReleaseExpiredReservations: ยกเลิกคำสั่งซื้อที่การจองสต็อกหมดอายุก่อนชำระเงิน เพื่อปล่อยสต็อกคืน
+// แต่ละคำสั่งซื้อมี Transaction ของตัวเอง คืนจำนวนคำสั่งซื้อที่ยกเลิก
+func ReleaseExpiredReservations(ctx context.Context, store repository.Store, now time.Time) (int, error) {
+	orderIDs, err := store.Reservations().ExpiredOrders(ctx, now, reservationSweepBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, orderID := range orderIDs {
+		expired := false
+		err := store.Transaction(ctx, func(tx repository.Store) error {
+			_, err := transitionOrder(ctx, tx, orderID, models.OrderStatusCancelled, nil, "stock reservation expired")
+			var illegal *illegalTransitionError
+			if errors.As(err, &illegal) {
+				// คำสั่งซื้อไม่ได้รอชำระเงินแล้ว (เช่น ชำระพร้อมกันกับการกวาด) การจองที่เหลือเป็นของค้าง ปล่อยทิ้งได้เลย
+				return tx.Reservations().DeleteByOrder(ctx, orderID)
+			}
+			expired = err == nil
+			return err
+		})
+		if err != nil {
+			return cancelled, err
+		}
+		if expired {
+			cancelled++
+		}
+	}
+	return cancelled, nil
+}
+
+// RunReservationSweeper: กวาดการจองที่หมดอายุทุก interval จนกว่า ctx จะถูกยกเลิก (รันเป็น Goroutine เบื้องหลัง)
+func RunReservationSweeper(ctx context.Context, store repository.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			cancelled, err := ReleaseExpiredReservations(ctx, store, now)
+			if err != nil {
+				log.Printf("reservations: กวาดการจองที่หมดอายุไม่สำเร็จ: %v", err)
+			}
+			if cancelled > 0 {
+				log.Printf("reservations: ยกเลิกคำสั่งซื้อที่การจองหมดอายุ %d รายการ", cancelled)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"strings"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/models"
+	"my-fiber-app/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// โหมดการค้นหาที่ใช้ได้ผลลัพธ์
+const (
+	searchModeFullText = "fulltext"
+	searchModeTrigram  = "trigram"
+)
+
+// เกณฑ์ความคล้ายขั้นต่ำของ Trigram (0-1) สำหรับการค้นหาแบบสะกดผิด
+const trigramThreshold = 0.3
+
+// SearchHit: หนังสือหนึ่งเล่มในผลการค้นหา พร้อมคะแนนและข้อความที่ไฮไลต์คำที่ตรง
+type SearchHit struct {
+	Book       models.Book       `json:"book"`
+	Available  int               `json:"available"` // สต็อกลบยอดที่ถูกจองไว้
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// SearchBooks: ค้นหาหนังสือจาก Title, Author และ Description (?q=&limit=)
+// ใช้ Full-text Search เป็นหลัก ถ้าไม่พบเลยจะลองค้นแบบ Trigram เพื่อรองรับการสะกดผิด
+func (h *BookHandler) SearchBooks(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. แยกคำค้น
+	q := strings.TrimSpace(c.Query("q"))
+	terms := search.Terms(q)
+	if len(terms) == 0 {
+		return apperr.ErrSearchQueryRequired
+	}
+	limit := clampLimit(c.QueryInt("limit", defaultPageLimit))
+
+	// 2. ค้นแบบ Full-text: คำภาษาไทยค้นแบบ Phrase ทีละอักษร คำอื่นค้นแบบขึ้นต้นด้วย (prefix)
+	rows, err := h.books.Search(ctx, terms, limit)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 3. ไม่พบเลย ลองค้นด้วยความคล้ายของตัวอักษร (Trigram) เผื่อพิมพ์ผิด
+	mode := searchModeFullText
+	if len(rows) == 0 {
+		mode = searchModeTrigram
+		plain := make([]string, 0, len(terms))
+		for _, t := range terms {
+			plain = append(plain, t.Text)
+		}
+		rows, err = h.books.SearchSimilar(ctx, strings.Join(plain, " "), trigramThreshold, limit)
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+	}
+
+	// 4. ไฮไลต์ช่วงข้อความที่ตรงกับคำค้นในแต่ละ Field และเติมสต็อกที่ขายได้
+	books := make([]models.Book, 0, len(rows))
+	for _, row := range rows {
+		books = append(books, row.Book)
+	}
+	reserved, err := h.reservedFor(ctx, books)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	hits := make([]SearchHit, 0, len(rows))
+	for _, row := range rows {
+		highlights := map[string]string{}
+		for field, text := range map[string]string{
+			"title":       row.Book.Title,
+			"author":      row.Book.Author,
+			"description": row.Book.Description,
+		} {
+			if snippet := search.Highlight(text, terms); snippet != "" {
+				highlights[field] = snippet
+			}
+		}
+		hits = append(hits, SearchHit{
+			Book:       row.Book,
+			Available:  max(row.Book.Stock-reserved[row.Book.ID], 0),
+			Rank:       row.Rank,
+			Highlights: highlights,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  hits,
+		"query": q,
+		"mode":  mode,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"strconv"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SettingsHandler: (Admin) ดูและปรับการตั้งค่าระบบ
+type SettingsHandler struct {
+	settings repository.SettingRepository
+}
+
+// NewSettingsHandler: สร้าง SettingsHandler
+func NewSettingsHandler(settings repository.SettingRepository) *SettingsHandler {
+	return &SettingsHandler{settings: settings}
+}
+
+// GetSettings: การตั้งค่าทั้งหมด (ค่าที่ยังไม่เคยตั้งจะแสดงเป็นค่าเริ่มต้น)
+func (h *SettingsHandler) GetSettings(c *fiber.Ctx) error {
+	stored, err := h.settings.List(c.UserContext())
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	values := make(map[string]string, len(models.DefaultSettings))
+	for key, value := range models.DefaultSettings {
+		values[key] = value
+	}
+	for _, s := range stored {
+		if _, known := values[s.Key]; known {
+			values[s.Key] = s.Value
+		}
+	}
+	return c.JSON(values)
+}
+
+// UpdateSetting: ตั้งค่าหนึ่งรายการ ({"value": "true"})
+func (h *SettingsHandler) UpdateSetting(c *fiber.Ctx) error {
+	key := c.Params("key")
+	if _, known := models.DefaultSettings[key]; !known {
+		return apperr.ErrSettingNotFound
+	}
+
+	type SettingInput struct {
+		Value string `json:"value" validate:"required,oneof=true false"`
+	}
+	input := new(SettingInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+	// ทุกการตั้งค่าตอนนี้เป็น Boolean เก็บในรูปแบบเดียวกันเสมอ ("true"/"false")
+	enabled, _ := strconv.ParseBool(input.Value)
+	value := strconv.FormatBool(enabled)
+
+	if err := h.settings.Set(c.UserContext(), key, value); err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	return c.JSON(fiber.Map{"key": key, "value": value})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// อายุของ Token: Access Token สั้น ใช้เรียก API / Refresh Token ยาว ใช้ขอ Access Token ใหม่
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// errInvalidRefreshToken: Refresh Token ไม่มีอยู่ หมดอายุ หรือถูกเพิกถอนแล้ว
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// randomToken: ค่าสุ่ม 32 ไบต์ (base64url) ใช้เป็น Refresh Token และรหัส Family
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken: ค่า Hash ที่เก็บในฐานข้อมูลแทนตัว Token จริง
+// Token เป็นค่าสุ่มยาวพอแล้ว จึงใช้ SHA-256 ได้เลยโดยไม่ต้องใช้ bcrypt
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// signAccessToken: สร้าง JWT อายุสั้นของผู้ใช้ ผูกกับ Session (Family ของ Refresh Token) เซ็นด้วย secret
+func signAccessToken(secret []byte, user *models.User, sessionID string) (string, error) {
+	jti, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": user.ID,                         // ระบุ ID ของผู้ใช้
+		"email":   user.Email,                      // ระบุ Email
+		"role":    user.Role,                       // ระบุบทบาท (Middleware จะตรวจซ้ำกับฐานข้อมูลเสมอ)
+		"jti":     jti,                             // รหัสของ Token นี้ ใช้เพิกถอนตอนออกจากระบบ
+		"sid":     sessionID,                       // Session ที่ออก Token นี้ (Family ของ Refresh Token)
+		"iat":     float64(now.UnixMilli()) / 1000, // เวลาที่ออก (ละเอียดระดับมิลลิวินาที) ใช้กับการออกจากระบบทุกเครื่อง
+		"exp":     now.Add(accessTokenTTL).Unix(),  // หมดอายุเร็ว ใช้ Refresh Token ขอใหม่
+	}
+
+	// สร้าง Object สำหรับ Token และเซ็นชื่อยืนยันด้วย Secret Key เดียวกับที่ JWT Middleware ใช้ตรวจ
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
+
+// issueRefreshToken: ออก Refresh Token ใหม่ใน Family ที่ระบุ คืนตัว Token จริง (เก็บเฉพาะ Hash)
+func issueRefreshToken(ctx context.Context, tokens repository.TokenRepository, userID uint, familyID string) (string, error) {
+	raw, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	err = tokens.Create(ctx, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	return raw, err
+}
+
+// revokeAllSessions: ออกจากระบบทุกเครื่องของผู้ใช้
+// Access Token ที่ออกไปแล้วใช้ไม่ได้ทันที และ Refresh Token ทุกตัวถูกเพิกถอน
+func revokeAllSessions(ctx context.Context, store repository.Store, userID uint) error {
+	now := time.Now()
+	return store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Revocations().RevokeUser(ctx, userID, now); err != nil {
+			return err
+		}
+		return tx.Tokens().RevokeUser(ctx, userID, now)
+	})
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/mail"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// disabledPassword: ค่าที่ใส่แทน Hash ของรหัสผ่านเมื่อผู้ดูแลบังคับตั้งรหัสผ่านใหม่
+// ไม่ใช่ bcrypt Hash ที่ถูกต้อง จึงไม่มีรหัสผ่านใดตรงกับค่านี้ (เข้าสู่ระบบได้อีกครั้งหลังตั้งรหัสผ่านใหม่เท่านั้น)
+const disabledPassword = "!"
+
+// UserHandler: (Admin) จัดการบัญชีผู้ใช้ ดูรายชื่อ เปลี่ยนบทบาท ระงับบัญชี และบังคับตั้งรหัสผ่านใหม่
+// ทุกการเปลี่ยนแปลงถูกบันทึกใน AuditLog ใน Transaction เดียวกัน
+type UserHandler struct {
+	store    repository.Store
+	mailer   mail.Mailer
+	resetURL string
+}
+
+// NewUserHandler: สร้าง UserHandler (frontendURL ใช้สร้างลิงก์ตั้งรหัสผ่านใหม่ในอีเมล)
+func NewUserHandler(store repository.Store, mailer mail.Mailer, frontendURL string) *UserHandler {
+	return &UserHandler{store: store, mailer: mailer, resetURL: frontendURL + "/reset-password"}
+}
+
+// GetUsers: รายชื่อผู้ใช้ (ใหม่ไปเก่า) ค้นด้วย ?q= (อีเมลหรือชื่อ) กรองด้วย ?role= และ ?status=active|suspended
+// แบ่งหน้าด้วย ?page=&limit=
+func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
+	// 1. อ่านและตรวจเงื่อนไขการค้นหา
+	type UserQuery struct {
+		Query  string `query:"q" json:"q" validate:"max=255"`
+		Role   string `query:"role" json:"role" validate:"max=50"`
+		Status string `query:"status" json:"status" validate:"omitempty,oneof=active suspended"`
+	}
+	input := new(UserQuery)
+	if err := c.QueryParser(input); err != nil {
+		return apperr.ErrBadRequest.Wrap(err)
+	}
+	if err := validateStruct(input, i18n.From(c)); err != nil {
+		return invalidInput(err)
+	}
+
+	filter := repository.UserFilter{Query: strings.TrimSpace(input.Query), Role: input.Role}
+	if input.Status != "" {
+		suspended := input.Status == "suspended"
+		filter.Suspended = &suspended
+	}
+
+	// 2. อ่านค่าการแบ่งหน้า พร้อมกำหนดขอบเขตที่อนุญาต
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := clampLimit(c.QueryInt("limit", defaultPageLimit))
+
+	users, total, err := h.store.Users().List(c.UserContext(), filter, limit, (page-1)*limit)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  users,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetUser: ข้อมูลผู้ใช้หนึ่งคน
+func (h *UserHandler) GetUser(c *fiber.Ctx) error {
+	user, err := h.targetUser(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(user)
+}
+
+// UpdateRole: เปลี่ยนบทบาทของผู้ใช้ ({"role": "admin"} เพื่อเลื่อนขั้น หรือ "user" เพื่อลดขั้น)
+// มีผลทันทีเพราะ RBAC อ่านบทบาทจากฐานข้อมูลทุกคำขอ เปลี่ยนบทบาทของตัวเองไม่ได้ (กันระบบไม่มีผู้ดูแลเหลือ)
+func (h *UserHandler) UpdateRole(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// 1. รับบทบาทใหม่ และตรวจว่ามีบทบาทนี้ในระบบ
+	type RoleInput struct {
+		Role string `json:"role" validate:"required,max=50"`
+	}
+	input := new(RoleInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+	if _, err := h.store.Users().GetRole(ctx, input.Role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrUnknownRole.With("role", input.Role)
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 2. หาผู้ใช้เป้าหมาย (ต้องไม่ใช่ตัวเอง)
+	user, err := h.targetOther(c)
+	if err != nil {
+		return err
+	}
+
+	// 3. บันทึกบทบาทใหม่พร้อม Audit (บทบาทเดิมอยู่แล้วไม่ต้องบันทึก)
+	if user.Role != input.Role {
+		err = h.store.Transaction(ctx, func(tx repository.Store) error {
+			if err := tx.Users().UpdateRole(ctx, user.ID, input.Role); err != nil {
+				return err
+			}
+			return recordAudit(c, tx, models.AuditLog{
+				Action:    models.AuditUserRoleChanged,
+				TargetID:  user.ID,
+				FromValue: user.Role,
+				ToValue:   input.Role,
+			})
+		})
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		user.Role = input.Role
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.role_updated"), "user": user})
+}
+
+// SuspendUser: ระงับบัญชี ({"reason": "..."}) แล้วออกจากระบบทุกเครื่อง
+// บัญชีที่ถูกระงับเข้าสู่ระบบ ขอ Token ใหม่ และใช้ Token เดิมไม่ได้จนกว่าจะถูกยกเลิกการระงับ
+func (h *UserHandler) SuspendUser(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	type SuspendInput struct {
+		Reason string `json:"reason" validate:"max=500"`
+	}
+	input := new(SuspendInput)
+	if len(c.Body()) > 0 {
+		if err := bindBody(c, input); err != nil {
+			return invalidInput(err)
+		}
+	}
+
+	user, err := h.targetOther(c)
+	if err != nil {
+		return err
+	}
+	if user.Suspended() {
+		return apperr.ErrAccountAlreadySuspended
+	}
+
+	now := time.Now()
+	reason := strings.TrimSpace(input.Reason)
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().Suspend(ctx, user.ID, now, reason); err != nil {
+			return err
+		}
+		if err := revokeAllSessions(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		return recordAudit(c, tx, models.AuditLog{
+			Action:   models.AuditUserSuspended,
+			TargetID: user.ID,
+			Reason:   reason,
+		})
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	user.SuspendedAt, user.SuspendReason = &now, reason
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.user_suspended"), "user": user})
+}
+
+// UnsuspendUser: ยกเลิกการระงับบัญชี (ผู้ใช้ต้องเข้าสู่ระบบใหม่ เพราะ Session เดิมถูกเพิกถอนไปแล้ว)
+func (h *UserHandler) UnsuspendUser(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	user, err := h.targetUser(c)
+	if err != nil {
+		return err
+	}
+	if !user.Suspended() {
+		return apperr.ErrAccountNotSuspended
+	}
+
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().Unsuspend(ctx, user.ID); err != nil {
+			return err
+		}
+		return recordAudit(c, tx, models.AuditLog{
+			Action:    models.AuditUserUnsuspended,
+			TargetID:  user.ID,
+			FromValue: user.SuspendReason,
+		})
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	user.SuspendedAt, user.SuspendReason = nil, ""
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.user_unsuspended"), "user": user})
+}
+
+// ForcePasswordReset: ยกเลิกรหัสผ่านเดิม ออกจากระบบทุกเครื่อง แล้วส่งลิงก์ตั้งรหัสผ่านใหม่ไปที่อีเมลของผู้ใช้
+// ใช้เมื่อสงสัยว่ารหัสผ่านรั่วไหล (ผู้ดูแลไม่เห็นและไม่ได้ตั้งรหัสผ่านให้เอง)
+func (h *UserHandler) ForcePasswordReset(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	user, err := h.targetUser(c)
+	if err != nil {
+		return err
+	}
+
+	// 1. ยกเลิกรหัสผ่านเดิมและทุก Session พร้อม Audit
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().UpdatePassword(ctx, user.ID, disabledPassword); err != nil {
+			return err
+		}
+		if err := revokeAllSessions(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		return recordAudit(c, tx, models.AuditLog{
+			Action:   models.AuditUserPasswordReset,
+			TargetID: user.ID,
+		})
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 2. ส่งลิงก์ตั้งรหัสผ่านใหม่ (ภาษาที่ผู้ใช้ตั้งไว้ ไม่ใช่ภาษาของผู้ดูแล)
+	// ถ้าส่งไม่สำเร็จ รหัสผ่านเดิมก็ถูกยกเลิกไปแล้ว ผู้ดูแลเรียกซ้ำ หรือผู้ใช้ขอลิงก์เองจากหน้าลืมรหัสผ่านได้
+	if err := issueResetLink(ctx, h.store, h.mailer, h.resetURL, user, i18n.Default, "mail.forced_reset"); err != nil {
+		log.Printf("forced password reset: %v", err)
+		return apperr.ErrMailDelivery.Wrap(err)
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.password_reset_forced"), "user_id": user.ID})
+}
+
+// RevokeSessions: บังคับให้ผู้ใช้ที่ระบุออกจากระบบทุกเครื่อง
+func (h *UserHandler) RevokeSessions(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	user, err := h.targetUser(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := revokeAllSessions(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		return recordAudit(c, tx, models.AuditLog{
+			Action:   models.AuditUserSessionsRevoked,
+			TargetID: user.ID,
+		})
+	})
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.sessions_revoked"), "user_id": user.ID})
+}
+
+// GetAuditLogs: บันทึกการกระทำของผู้ดูแล (ใหม่ไปเก่า)
+// กรองด้วย ?actor_id=, ?user_id= (ผู้ใช้ที่ถูกกระทำ), ?action= และแบ่งหน้าด้วย ?page=&limit=
+func (h *UserHandler) GetAuditLogs(c *fiber.Ctx) error {
+	type AuditQuery struct {
+		ActorID uint   `query:"actor_id" json:"actor_id"`
+		UserID  uint   `query:"user_id" json:"user_id"`
+		Action  string `query:"action" json:"action" validate:"max=100"`
+	}
+	input := new(AuditQuery)
+	if err := c.QueryParser(input); err != nil {
+		return apperr.ErrBadRequest.Wrap(err)
+	}
+	if err := validateStruct(input, i18n.From(c)); err != nil {
+		return invalidInput(err)
+	}
+
+	filter := repository.AuditLogFilter{ActorID: input.ActorID, Action: input.Action}
+	if input.UserID != 0 {
+		filter.TargetType, filter.TargetID = models.AuditTargetUser, input.UserID
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := clampLimit(c.QueryInt("limit", defaultPageLimit))
+
+	entries, total, err := h.store.AuditLogs().List(c.UserContext(), filter, limit, (page-1)*limit)
+	if err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	if entries == nil {
+		entries = []models.AuditLog{}
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  entries,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// targetUser: ผู้ใช้ตาม :id ใน Path
+func (h *UserHandler) targetUser(c *fiber.Ctx) (*models.User, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return nil, apperr.ErrInvalidID
+	}
+	user, err := h.store.Users().Get(c.UserContext(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, apperr.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, apperr.ErrInternal.Wrap(err)
+	}
+	return user, nil
+}
+
+// targetOther: เหมือน targetUser แต่ต้องไม่ใช่บัญชีของผู้ดูแลที่ทำรายการเอง
+func (h *UserHandler) targetOther(c *fiber.Ctx) (*models.User, error) {
+	user, err := h.targetUser(c)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == getUserID(c) {
+		return nil, apperr.ErrCannotModifySelf
+	}
+	return user, nil
+}
+
+// recordAudit: บันทึกการกระทำของผู้ดูแลที่ทำ Request นี้ (TargetType ว่าง = ผู้ใช้)
+// เรียกภายใน Transaction เดียวกับการกระทำ ถ้าบันทึกไม่สำเร็จการกระทำก็ถูกยกเลิกด้วย
+func recordAudit(c *fiber.Ctx, tx repository.Store, entry models.AuditLog) error {
+	entry.ActorID = getUserID(c)
+	if entry.TargetType == "" {
+		entry.TargetType = models.AuditTargetUser
+	}
+	entry.RequestID = c.GetRespHeader(fiber.HeaderXRequestID)
+	return tx.AuditLogs().Create(c.UserContext(), &entry)
+}
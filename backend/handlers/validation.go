@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// validate: ตัวตรวจข้อมูลตาม Tag `validate:"..."` ใช้ร่วมกันทุก Handler (ปลอดภัยต่อการใช้พร้อมกัน)
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// รายงานชื่อ Field ตาม Tag json (เช่น "book_id") ให้ตรงกับที่ Client ส่งมา
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// fieldError: Field หนึ่งที่ไม่ผ่านการตรวจ
+type fieldError struct {
+	Field   string `json:"field"`   // ชื่อตาม JSON (Field ซ้อนคั่นด้วยจุด)
+	Code    string `json:"code"`    // รหัสสำหรับโปรแกรม เช่น "required", "too_short"
+	Message string `json:"message"` // ข้อความสำหรับแสดงผู้ใช้ (ตามภาษาของ Request)
+}
+
+// validationError: ข้อมูลที่ส่งมาไม่ผ่านการตรวจ อย่างน้อยหนึ่ง Field
+type validationError struct {
+	Fields []fieldError
+}
+
+func (e *validationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// bindBody: อ่าน Request Body ลงใน input แล้วตรวจตาม Tag validate
+// คืน *validationError ถ้าข้อมูลไม่ผ่านการตรวจ หรือ Error อื่นถ้าอ่าน Body ไม่ได้
+func bindBody(c *fiber.Ctx, input interface{}) error {
+	if err := c.BodyParser(input); err != nil {
+		return err
+	}
+	return validateStruct(input, i18n.From(c))
+}
+
+// validateStruct: ตรวจ Struct ตาม Tag validate แล้วแปลงผลเป็น *validationError (ข้อความเป็นภาษา lang)
+func validateStruct(input interface{}, lang i18n.Language) error {
+	err := validate.Struct(input)
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	fields := make([]fieldError, 0, len(errs))
+	for _, fe := range errs {
+		code := fieldErrorCode(fe)
+		fields = append(fields, fieldError{
+			Field:   fieldPath(fe),
+			Code:    code,
+			Message: fieldErrorMessage(lang, code, fe.Param()),
+		})
+	}
+	return &validationError{Fields: fields}
+}
+
+// invalidInput: แปลง Error จาก bindBody เป็น Error ที่ส่งกลับให้ Client
+// ข้อมูลผิดเงื่อนไขได้ 422 พร้อมรายการ Field ส่วน Body ที่อ่านไม่ได้ได้ 400
+func invalidInput(err error) error {
+	var verr *validationError
+	if errors.As(err, &verr) {
+		return apperr.ErrValidation.With("fields", verr.Fields)
+	}
+	return apperr.ErrBadRequest.Wrap(err)
+}
+
+// fieldPath: ชื่อ Field แบบเต็ม ไม่รวมชื่อ Struct ชั้นนอกสุด (เช่น "items.0.quantity")
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// fieldErrorCode: แปลง Tag ที่ไม่ผ่านเป็นรหัสที่คงที่ (min/max แยกตามชนิดข้อมูล: ความยาวข้อความหรือค่าตัวเลข)
+func fieldErrorCode(fe validator.FieldError) string {
+	isText := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return "required"
+	case "email":
+		return "invalid_email"
+	case "url", "http_url":
+		return "invalid_url"
+	case "oneof":
+		return "invalid_choice"
+	case "min", "gte":
+		if isText {
+			return "too_short"
+		}
+		return "too_small"
+	case "max", "lte":
+		if isText {
+			return "too_long"
+		}
+		return "too_large"
+	default:
+		return "invalid"
+	}
+}
+
+// fieldErrorMessage: ข้อความของแต่ละรหัสจากแคตตาล็อก (Key "field.<code>")
+// param คือค่าใน Tag เช่น "3" ของ min=3 ใช้เฉพาะรหัสที่ข้อความมีตัวแทนที่
+func fieldErrorMessage(lang i18n.Language, code, param string) string {
+	switch code {
+	case "invalid_choice", "too_short", "too_long", "too_small", "too_large":
+		return i18n.T(lang, "field."+code, param)
+	default:
+		return i18n.T(lang, "field."+code)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/mail"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// อายุของลิงก์ยืนยันอีเมล
+const emailVerificationTTL = 24 * time.Hour
+
+// errInvalidVerification: ลิงก์ยืนยันอีเมลถูกแก้ไข หมดอายุ หรือไม่ตรงกับอีเมลปัจจุบันของผู้ใช้
+var errInvalidVerification = errors.New("invalid email verification token")
+
+// verificationClaims: ข้อมูลที่เซ็นไว้ในลิงก์ยืนยันอีเมล
+// ผูกกับอีเมล ถ้าผู้ใช้เปลี่ยนอีเมล ลิงก์เก่าจะใช้ไม่ได้
+type verificationClaims struct {
+	UserID    uint   `json:"uid"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// verificationKey: กุญแจสำหรับเซ็นลิงก์ยืนยันอีเมล แยกจากกุญแจของ JWT (Derive จาก JWT Secret)
+// ลิงก์จึงนำไปใช้เป็น Access Token ไม่ได้ และในทางกลับกัน
+func verificationKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("email-verification"))
+	return mac.Sum(nil)
+}
+
+// signVerification: สร้าง Token ของลิงก์ยืนยันอีเมล (payload.signature แบบ base64url) ไม่ต้องเก็บในฐานข้อมูล
+func signVerification(secret []byte, user *models.User) (string, error) {
+	payload, err := json.Marshal(verificationClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(emailVerificationTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, verificationKey(secret))
+	mac.Write([]byte(encoded))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// parseVerification: ตรวจลายเซ็นและวันหมดอายุของ Token แล้วคืนข้อมูลที่เซ็นไว้
+func parseVerification(secret []byte, token string) (*verificationClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidVerification
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, errInvalidVerification
+	}
+	mac := hmac.New(sha256.New, verificationKey(secret))
+	mac.Write([]byte(encoded))
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return nil, errInvalidVerification
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidVerification
+	}
+	var claims verificationClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errInvalidVerification
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, errInvalidVerification
+	}
+	return &claims, nil
+}
+
+// sendVerificationEmail: ส่งลิงก์ยืนยันอีเมลไปหาผู้ใช้ (ภาษาตามที่ผู้ใช้ตั้งไว้ ถ้าไม่ได้ตั้งใช้ lang)
+func (h *AuthHandler) sendVerificationEmail(ctx context.Context, user *models.User, lang i18n.Language) error {
+	token, err := signVerification(h.secret, user)
+	if err != nil {
+		return err
+	}
+	link := h.verifyURL + "?token=" + url.QueryEscape(token)
+	lang = i18n.Preferred(user.Language, lang)
+	return h.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "mail.verify_email.subject"),
+		Body:    i18n.T(lang, "mail.verify_email.body", user.Name, link),
+	})
+}
+
+// VerifyEmail: ยืนยันอีเมลด้วย Token จากลิงก์ในอีเมล (เรียกซ้ำได้ ผลเหมือนเดิม)
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	type VerifyInput struct {
+		Token string `json:"token" validate:"required"`
+	}
+	input := new(VerifyInput)
+	if err := bindBody(c, input); err != nil {
+		return invalidInput(err)
+	}
+
+	// 1. ตรวจลายเซ็นและวันหมดอายุ แล้วตรวจว่าอีเมลในลิงก์ยังเป็นอีเมลปัจจุบันของผู้ใช้
+	claims, err := parseVerification(h.secret, input.Token)
+	var user *models.User
+	if err == nil {
+		user, err = h.store.Users().Get(ctx, claims.UserID)
+	}
+	if err == nil && user.Email != claims.Email {
+		err = errInvalidVerification
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidVerification) || errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrInvalidVerificationToken
+		}
+		return apperr.ErrInternal.Wrap(err)
+	}
+
+	// 2. บันทึกเวลายืนยัน (ถ้ายืนยันไว้แล้วจะไม่เปลี่ยน)
+	if err := h.store.Users().MarkEmailVerified(ctx, user.ID, time.Now()); err != nil {
+		return apperr.ErrInternal.Wrap(err)
+	}
+	return c.JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.email_verified"), "email": user.Email})
+}
+
+// ResendVerification: ส่งลิงก์ยืนยันอีเมลใหม่ให้ผู้ใช้ที่เข้าสู่ระบบอยู่
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	user, err := h.store.Users().Get(ctx, getUserID(c))
+	if err != nil {
+		return apperr.ErrUserNotFound
+	}
+	if user.EmailVerified() {
+		return apperr.ErrEmailAlreadyVerified
+	}
+
+	if err := h.sendVerificationEmail(ctx, user, i18n.From(c)); err != nil {
+		log.Printf("email verification: ส่งอีเมลไม่สำเร็จ: %v", err)
+		return apperr.ErrMailDelivery.Wrap(err)
+	}
+	return c.Status(202).JSON(fiber.Map{"message": i18n.T(i18n.From(c), "message.verification_sent", user.Email)})
+}
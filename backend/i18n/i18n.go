@@ -0,0 +1,136 @@
+// Package i18n: แปลข้อความที่ API ส่งกลับ (ภาษาไทยและภาษาอังกฤษ)
+// ข้อความทุกข้อความอยู่ในแคตตาล็อก (messages.go) โดยมี Key คงที่ เช่น "error.book_not_found", "message.login_success"
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language: ภาษาที่รองรับ (รหัสตาม BCP 47 แบบสั้น)
+type Language string
+
+const (
+	Thai    Language = "th"
+	English Language = "en"
+
+	// Default: ภาษาเมื่อ Client ไม่ได้ระบุ หรือระบุภาษาที่ไม่รองรับ
+	Default = Thai
+)
+
+// Supported: ภาษาที่รองรับทั้งหมด (ทุก Key ในแคตตาล็อกต้องมีครบทุกภาษา)
+var Supported = []Language{Thai, English}
+
+// localsKey: Key ใน c.Locals ที่เก็บภาษาของ Request
+const localsKey = "lang"
+
+// Parse: แปลงข้อความเป็น Language ที่รองรับ (ไม่สนตัวพิมพ์เล็กใหญ่และ Region เช่น "en-US" -> English)
+func Parse(s string) (Language, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	for _, lang := range Supported {
+		if string(lang) == primary {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate: เลือกภาษาที่ดีที่สุดจาก Header Accept-Language (ตามค่า q) ถ้าไม่มีภาษาที่รองรับใช้ Default
+func Negotiate(c *fiber.Ctx) Language {
+	offers := make([]string, len(Supported))
+	for i, lang := range Supported {
+		offers[i] = string(lang)
+	}
+	if lang, ok := Parse(c.AcceptsLanguages(offers...)); ok {
+		return lang
+	}
+	return Default
+}
+
+// Set: กำหนดภาษาของ Request นี้ และตอบ Header Content-Language ให้ตรงกัน
+func Set(c *fiber.Ctx, lang Language) {
+	c.Locals(localsKey, lang)
+	c.Set(fiber.HeaderContentLanguage, string(lang))
+}
+
+// SetPreferred: ใช้ภาษาที่ผู้ใช้ตั้งไว้ (ค่าว่างหรือภาษาที่ไม่รองรับ = ไม่เปลี่ยน)
+func SetPreferred(c *fiber.Ctx, preference string) {
+	if lang, ok := Parse(preference); ok {
+		Set(c, lang)
+	}
+}
+
+// From: ภาษาของ Request นี้ (ถ้ายังไม่ได้เลือกไว้ใช้ Default)
+func From(c *fiber.Ctx) Language {
+	if lang, ok := c.Locals(localsKey).(Language); ok {
+		return lang
+	}
+	return Default
+}
+
+// Preferred: ภาษาที่ผู้ใช้ตั้งไว้ ถ้าไม่ได้ตั้งใช้ fallback (ใช้กับงานเบื้องหลัง เช่น ส่งอีเมล)
+func Preferred(preference string, fallback Language) Language {
+	if lang, ok := Parse(preference); ok {
+		return lang
+	}
+	return fallback
+}
+
+// Has: แคตตาล็อกมี Key นี้หรือไม่
+func Has(key string) bool {
+	_, ok := catalog[key]
+	return ok
+}
+
+// T: ข้อความของ Key ในภาษาที่ต้องการ (args แทนที่ %s/%d ในข้อความ)
+// ถ้าไม่มีคำแปลในภาษานั้นใช้ Default ถ้าไม่มี Key เลยคืน Key กลับไป
+func T(lang Language, key string, args ...interface{}) string {
+	translations, ok := catalog[key]
+	if !ok {
+		log.Printf("i18n: ไม่พบข้อความ %q", key)
+		return key
+	}
+	msg, ok := translations[lang]
+	if !ok {
+		msg = translations[Default]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Check: ตรวจว่าแคตตาล็อกครบ: ทุก Key มีคำแปลครบทุกภาษา จำนวนตัวแทนที่ (%s) ตรงกัน
+// และมี Key ที่จำเป็นทั้งหมด (เช่น รหัส Error ทุกตัว) เรียกจาก i18n_test.go
+func Check(required []string) error {
+	var problems []string
+	for _, key := range required {
+		if !Has(key) {
+			problems = append(problems, fmt.Sprintf("%s: ไม่มีในแคตตาล็อก", key))
+		}
+	}
+	for key, translations := range catalog {
+		verbs := -1
+		for _, lang := range Supported {
+			msg, ok := translations[lang]
+			if !ok || msg == "" {
+				problems = append(problems, fmt.Sprintf("%s: ไม่มีคำแปลภาษา %s", key, lang))
+				continue
+			}
+			n := strings.Count(msg, "%") - 2*strings.Count(msg, "%%")
+			if verbs >= 0 && n != verbs {
+				problems = append(problems, fmt.Sprintf("%s: จำนวนตัวแทนที่ในภาษา %s ไม่ตรงกับภาษาอื่น", key, lang))
+			}
+			verbs = n
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("i18n: แคตตาล็อกไม่ครบ:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
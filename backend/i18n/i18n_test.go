@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"my-fiber-app/apperr"
+)
+
+// keyLiteral: Key ของแคตตาล็อกที่เขียนเป็นข้อความตรงๆ ในโค้ด เช่น "message.login_success"
+// หรือชื่อแม่แบบอีเมลอย่าง "mail.reset_password" (ใช้คู่กับ .subject และ .body)
+var keyLiteral = regexp.MustCompile(`"((?:message|mail|field)\.[a-z_]+(?:\.[a-z_]+)*)"`)
+
+// fieldCodes: รหัสของ fieldErrorCode ใน handlers/validation.go (Key ประกอบขึ้นตอนรัน "field."+code จึงค้นจากโค้ดไม่เจอ)
+var fieldCodes = []string{
+	"required", "invalid_email", "invalid_url", "invalid_choice",
+	"too_short", "too_long", "too_small", "too_large", "invalid",
+}
+
+// usedKeys: Key ทั้งหมดที่โค้ดของแอปใช้ ค้นจากไฟล์ .go ทุกไฟล์นอกแพ็กเกจ i18n (ไม่รวมไฟล์ทดสอบ)
+func usedKeys(t *testing.T) []string {
+	t.Helper()
+	seen := map[string]bool{}
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "i18n" || d.Name() == "node_modules" || (strings.HasPrefix(d.Name(), ".") && path != "..") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range keyLiteral.FindAllStringSubmatch(string(src), -1) {
+			key := m[1]
+			if strings.HasPrefix(key, "mail.") && strings.Count(key, ".") == 1 {
+				seen[key+".subject"], seen[key+".body"] = true, true
+				continue
+			}
+			seen[key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range fieldCodes {
+		seen["field."+code] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TestCatalogComplete: ทุก Key มีคำแปลครบทุกภาษา ตัวแทนที่ตรงกัน มีข้อความของทุกรหัส Error ใน apperr
+// และมีทุก Key ที่โค้ดเรียกใช้ (message.*, mail.*, field.*) ขาด Key ไหนผู้ใช้จะเห็นชื่อ Key แทนข้อความ
+func TestCatalogComplete(t *testing.T) {
+	required := usedKeys(t)
+	if len(required) < len(fieldCodes)+10 {
+		t.Fatalf("found only %d keys in the source (%v), the scan is probably broken", len(required), required)
+	}
+	for _, code := range apperr.Codes() {
+		required = append(required, "error."+code)
+	}
+	if err := Check(required); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,370 @@
+package i18n
+
+// catalog: ข้อความทั้งหมดแยกตาม Key แล้วตามภาษา
+// เพิ่ม Key ใหม่ต้องใส่ครบทุกภาษาใน Supported (ถ้าขาด go test ./i18n จะไม่ผ่าน)
+//
+//	error.<code>   รายละเอียด (detail) ของ Error แต่ละรหัสใน apperr
+//	field.<code>   ข้อความของ Field ที่ไม่ผ่านการตรวจ (รหัสเดียวกับ fields[].code)
+//	message.<name> ข้อความเมื่อทำรายการสำเร็จ
+//	mail.<name>    หัวเรื่องและเนื้อหาอีเมล
+var catalog = map[string]map[Language]string{
+	// --- Error ทั่วไป ---
+	"error.bad_request": {
+		Thai:    "ข้อมูลที่ส่งมาไม่ถูกต้อง",
+		English: "The request body could not be read.",
+	},
+	"error.validation_failed": {
+		Thai:    "ข้อมูลที่ส่งมาไม่ผ่านการตรวจสอบ",
+		English: "Some fields are invalid.",
+	},
+	"error.invalid_id": {
+		Thai:    "รหัสที่ระบุใน URL ไม่ถูกต้อง",
+		English: "The ID in the URL is invalid.",
+	},
+	"error.unauthorized": {
+		Thai:    "ไม่ได้รับอนุญาต: บัตรผ่านไม่ถูกต้องหรือหมดอายุ",
+		English: "Unauthorized: the access token is invalid or has expired.",
+	},
+	"error.token_revoked": {
+		Thai:    "ไม่ได้รับอนุญาต: บัตรผ่านถูกยกเลิกแล้ว กรุณาเข้าสู่ระบบใหม่",
+		English: "Unauthorized: this access token has been revoked. Please log in again.",
+	},
+	"error.forbidden": {
+		Thai:    "ไม่มีสิทธิ์เข้าถึง",
+		English: "You don't have permission to do this.",
+	},
+	"error.not_found": {
+		Thai:    "ไม่พบหน้าที่ต้องการ",
+		English: "The requested resource was not found.",
+	},
+	"error.internal_error": {
+		Thai:    "เกิดข้อผิดพลาดภายในระบบ กรุณาลองใหม่ภายหลัง",
+		English: "Something went wrong on our side. Please try again later.",
+	},
+
+	// --- บัญชีผู้ใช้และการเข้าสู่ระบบ ---
+	"error.email_taken": {
+		Thai:    "อีเมลนี้มีในระบบแล้ว",
+		English: "This email is already registered.",
+	},
+	"error.invalid_credentials": {
+		Thai:    "อีเมลหรือรหัสผ่านไม่ถูกต้อง",
+		English: "Incorrect email or password.",
+	},
+	"error.invalid_refresh_token": {
+		Thai:    "Refresh Token ไม่ถูกต้องหรือหมดอายุ",
+		English: "The refresh token is invalid or has expired.",
+	},
+	"error.refresh_token_reused": {
+		Thai:    "Refresh Token นี้ถูกใช้ไปแล้ว ระบบได้ยกเลิกการเข้าสู่ระบบนี้ กรุณาเข้าสู่ระบบใหม่",
+		English: "This refresh token was already used, so the session has been revoked. Please log in again.",
+	},
+	"error.invalid_reset_token": {
+		Thai:    "ลิงก์ตั้งรหัสผ่านใหม่ไม่ถูกต้อง หมดอายุ หรือถูกใช้ไปแล้ว",
+		English: "The password reset link is invalid, has expired or was already used.",
+	},
+	"error.invalid_verification_token": {
+		Thai:    "ลิงก์ยืนยันอีเมลไม่ถูกต้องหรือหมดอายุ",
+		English: "The email verification link is invalid or has expired.",
+	},
+	"error.email_not_verified": {
+		Thai:    "กรุณายืนยันอีเมลก่อนสั่งซื้อ",
+		English: "Please verify your email before checking out.",
+	},
+	"error.email_already_verified": {
+		Thai:    "อีเมลนี้ยืนยันแล้ว",
+		English: "This email is already verified.",
+	},
+	"error.user_not_found": {
+		Thai:    "ไม่พบผู้ใช้",
+		English: "User not found.",
+	},
+	"error.mail_delivery_failed": {
+		Thai:    "ไม่สามารถส่งอีเมลได้ กรุณาลองใหม่ภายหลัง",
+		English: "The email could not be sent. Please try again later.",
+	},
+	"error.account_suspended": {
+		Thai:    "บัญชีนี้ถูกระงับการใช้งาน กรุณาติดต่อผู้ดูแลระบบ",
+		English: "This account has been suspended. Please contact support.",
+	},
+
+	// --- การจัดการผู้ใช้โดยผู้ดูแล ---
+	"error.unknown_role": {
+		Thai:    "ไม่มีบทบาทนี้ในระบบ",
+		English: "Unknown role.",
+	},
+	"error.cannot_modify_self": {
+		Thai:    "ไม่สามารถเปลี่ยนบทบาทหรือระงับบัญชีของตัวเองได้",
+		English: "You cannot change the role of or suspend your own account.",
+	},
+	"error.account_already_suspended": {
+		Thai:    "บัญชีนี้ถูกระงับอยู่แล้ว",
+		English: "This account is already suspended.",
+	},
+	"error.account_not_suspended": {
+		Thai:    "บัญชีนี้ไม่ได้ถูกระงับ",
+		English: "This account is not suspended.",
+	},
+
+	// --- หนังสือและการค้นหา ---
+	"error.book_not_found": {
+		Thai:    "ไม่พบหนังสือที่ต้องการ",
+		English: "Book not found.",
+	},
+	"error.invalid_sort": {
+		Thai:    "ไม่รองรับการเรียงลำดับนี้",
+		English: "This sort field is not supported.",
+	},
+	"error.invalid_sort_order": {
+		Thai:    "order ต้องเป็น asc หรือ desc",
+		English: "order must be asc or desc.",
+	},
+	"error.invalid_price_filter": {
+		Thai:    "ช่วงราคาต้องเป็นตัวเลข",
+		English: "The price range must be a number.",
+	},
+	"error.invalid_cursor": {
+		Thai:    "cursor ไม่ถูกต้อง",
+		English: "The cursor is invalid.",
+	},
+	"error.search_query_required": {
+		Thai:    "กรุณาระบุคำค้นหา",
+		English: "Please enter a search query.",
+	},
+
+	// --- สต็อกและสมุดบัญชีสต็อก ---
+	"error.stock_managed_by_inventory": {
+		Thai:    "แก้สต็อกจากข้อมูลหนังสือไม่ได้ ให้บันทึกการเคลื่อนไหวที่ /admin/inventory แทน",
+		English: "Stock can't be edited with the book. Post a stock movement to /admin/inventory instead.",
+	},
+	"error.invalid_movement_quantity": {
+		Thai:    "จำนวนต้องเป็นค่าบวกสำหรับการเคลื่อนไหวชนิดนี้ (ชนิด adjustment เท่านั้นที่ติดลบได้)",
+		English: "Quantity must be positive for this movement type. Only adjustments can be negative.",
+	},
+
+	// --- ตะกร้าสินค้าและคำสั่งซื้อ ---
+	"error.cart_item_not_found": {
+		Thai:    "ไม่พบสินค้าในตะกร้า",
+		English: "Cart item not found.",
+	},
+	"error.cart_empty": {
+		Thai:    "ตะกร้าสินค้าว่างเปล่า",
+		English: "Your cart is empty.",
+	},
+	"error.insufficient_stock": {
+		Thai:    "จำนวนสินค้าในคลังไม่พอ",
+		English: "Not enough stock.",
+	},
+	"error.order_not_found": {
+		Thai:    "ไม่พบคำสั่งซื้อ",
+		English: "Order not found.",
+	},
+	"error.order_not_pending": {
+		Thai:    "คำสั่งซื้อนี้ไม่อยู่ในสถานะรอชำระเงิน",
+		English: "This order is not awaiting payment.",
+	},
+	"error.illegal_transition": {
+		Thai:    "ไม่สามารถเปลี่ยนสถานะคำสั่งซื้อได้",
+		English: "The order can't move to this status.",
+	},
+
+	// --- การชำระเงิน ---
+	"error.payment_not_found": {
+		Thai:    "ไม่พบรายการชำระเงิน",
+		English: "Payment not found.",
+	},
+	"error.invalid_webhook_signature": {
+		Thai:    "ลายเซ็น Webhook ไม่ถูกต้อง",
+		English: "The webhook signature is invalid.",
+	},
+	"error.payment_gateway_error": {
+		Thai:    "ไม่สามารถเชื่อมต่อผู้ให้บริการชำระเงินได้",
+		English: "Could not reach the payment provider.",
+	},
+	"error.refund_failed": {
+		Thai:    "ไม่สามารถคืนเงินผ่านผู้ให้บริการได้",
+		English: "The payment provider could not process the refund.",
+	},
+	"error.capture_failed": {
+		Thai:    "ไม่สามารถจำลองการชำระเงินได้",
+		English: "The simulated payment could not be captured.",
+	},
+	"error.slip_already_used": {
+		Thai:    "เลขอ้างอิงสลิปนี้ถูกใช้ไปแล้ว",
+		English: "This slip reference has already been used.",
+	},
+	"error.promptpay_not_configured": {
+		Thai:    "ยังไม่ได้ตั้งค่าหมายเลข PromptPay ของร้าน",
+		English: "The store's PromptPay ID is not configured.",
+	},
+
+	// --- การตั้งค่าระบบ ---
+	"error.setting_not_found": {
+		Thai:    "ไม่พบการตั้งค่านี้",
+		English: "Setting not found.",
+	},
+
+	// --- Field ที่ไม่ผ่านการตรวจ (%s คือค่าใน Tag เช่น "3" ของ min=3) ---
+	"field.required": {
+		Thai:    "จำเป็นต้องระบุ",
+		English: "is required",
+	},
+	"field.invalid_email": {
+		Thai:    "รูปแบบอีเมลไม่ถูกต้อง",
+		English: "must be a valid email address",
+	},
+	"field.invalid_url": {
+		Thai:    "รูปแบบ URL ไม่ถูกต้อง",
+		English: "must be a valid URL",
+	},
+	"field.invalid_choice": {
+		Thai:    "ต้องเป็นค่าใดค่าหนึ่งต่อไปนี้: %s",
+		English: "must be one of: %s",
+	},
+	"field.too_short": {
+		Thai:    "ต้องมีอย่างน้อย %s ตัวอักษร",
+		English: "must be at least %s characters",
+	},
+	"field.too_long": {
+		Thai:    "ต้องมีไม่เกิน %s ตัวอักษร",
+		English: "must be at most %s characters",
+	},
+	"field.too_small": {
+		Thai:    "ต้องไม่น้อยกว่า %s",
+		English: "must be at least %s",
+	},
+	"field.too_large": {
+		Thai:    "ต้องไม่มากกว่า %s",
+		English: "must be at most %s",
+	},
+	"field.invalid": {
+		Thai:    "ค่าไม่ถูกต้อง",
+		English: "is invalid",
+	},
+
+	// --- ทำรายการสำเร็จ ---
+	"message.signup_success": {
+		Thai:    "สมัครสมาชิกสำเร็จ กรุณายืนยันอีเมลจากลิงก์ที่ส่งไปให้",
+		English: "Sign-up complete. Please verify your email with the link we sent you.",
+	},
+	"message.login_success": {
+		Thai:    "เข้าสู่ระบบสำเร็จ",
+		English: "Logged in.",
+	},
+	"message.logout_success": {
+		Thai:    "ออกจากระบบสำเร็จ",
+		English: "Logged out.",
+	},
+	"message.logout_all_success": {
+		Thai:    "ออกจากระบบทุกเครื่องสำเร็จ",
+		English: "Logged out on all devices.",
+	},
+	"message.sessions_revoked": {
+		Thai:    "ยกเลิกการเข้าสู่ระบบทุกเครื่องของผู้ใช้แล้ว",
+		English: "All of the user's sessions have been revoked.",
+	},
+	"message.language_updated": {
+		Thai:    "บันทึกภาษาที่ใช้แล้ว",
+		English: "Language preference saved.",
+	},
+	"message.role_updated": {
+		Thai:    "เปลี่ยนบทบาทของผู้ใช้แล้ว",
+		English: "The user's role has been updated.",
+	},
+	"message.user_suspended": {
+		Thai:    "ระงับบัญชีผู้ใช้แล้ว และออกจากระบบทุกเครื่อง",
+		English: "The account has been suspended and all of its sessions revoked.",
+	},
+	"message.user_unsuspended": {
+		Thai:    "ยกเลิกการระงับบัญชีผู้ใช้แล้ว",
+		English: "The account has been reinstated.",
+	},
+	"message.password_reset_forced": {
+		Thai:    "ยกเลิกรหัสผ่านเดิมและส่งลิงก์ตั้งรหัสผ่านใหม่ไปที่อีเมลของผู้ใช้แล้ว",
+		English: "The user's password has been invalidated and a reset link has been emailed to them.",
+	},
+	"message.password_reset_requested": {
+		Thai:    "ถ้าอีเมลนี้มีในระบบ เราได้ส่งลิงก์สำหรับตั้งรหัสผ่านใหม่ไปให้แล้ว",
+		English: "If this email is registered, we have sent it a link to set a new password.",
+	},
+	"message.password_reset_success": {
+		Thai:    "ตั้งรหัสผ่านใหม่สำเร็จ กรุณาเข้าสู่ระบบอีกครั้ง",
+		English: "Your password has been changed. Please log in again.",
+	},
+	"message.email_verified": {
+		Thai:    "ยืนยันอีเมลสำเร็จ",
+		English: "Email verified.",
+	},
+	"message.verification_sent": {
+		Thai:    "ส่งลิงก์ยืนยันอีเมลไปที่ %s แล้ว",
+		English: "A verification link has been sent to %s.",
+	},
+	"message.book_deleted": {
+		Thai:    "ลบหนังสือสำเร็จ",
+		English: "Book deleted.",
+	},
+	"message.cart_item_added": {
+		Thai:    "เพิ่มสินค้าลงตะกร้าสำเร็จ",
+		English: "Added to cart.",
+	},
+	"message.cart_item_updated": {
+		Thai:    "อัปเดตจำนวนสินค้าสำเร็จ",
+		English: "Quantity updated.",
+	},
+	"message.cart_item_removed": {
+		Thai:    "ลบสินค้าออกจากตะกร้าสำเร็จ",
+		English: "Removed from cart.",
+	},
+	"message.stock_movement_recorded": {
+		Thai:    "บันทึกการเคลื่อนไหวของสต็อกสำเร็จ",
+		English: "Stock movement recorded.",
+	},
+	"message.payment_confirmed": {
+		Thai:    "ยืนยันการชำระเงินสำเร็จ",
+		English: "Payment confirmed.",
+	},
+
+	// --- อีเมล (%s ตัวแรกคือชื่อผู้ใช้ ตัวที่สองคือลิงก์) ---
+	"mail.verify_email.subject": {
+		Thai:    "ยืนยันอีเมล - Space Book Store",
+		English: "Verify your email - Space Book Store",
+	},
+	"mail.verify_email.body": {
+		Thai: "สวัสดีคุณ %s\n\n" +
+			"กดลิงก์ด้านล่างภายใน 24 ชั่วโมงเพื่อยืนยันอีเมลของคุณ:\n\n" +
+			"%s\n\n" +
+			"ถ้าคุณไม่ได้สมัครสมาชิก ไม่ต้องทำอะไร\n",
+		English: "Hello %s,\n\n" +
+			"Open the link below within 24 hours to verify your email:\n\n" +
+			"%s\n\n" +
+			"If you didn't sign up, you can ignore this email.\n",
+	},
+	"mail.reset_password.subject": {
+		Thai:    "ตั้งรหัสผ่านใหม่ - Space Book Store",
+		English: "Reset your password - Space Book Store",
+	},
+	"mail.reset_password.body": {
+		Thai: "สวัสดีคุณ %s\n\n" +
+			"เราได้รับคำขอตั้งรหัสผ่านใหม่สำหรับบัญชีของคุณ กดลิงก์ด้านล่างภายใน 1 ชั่วโมง:\n\n" +
+			"%s\n\n" +
+			"ถ้าคุณไม่ได้เป็นผู้ขอ ไม่ต้องทำอะไร รหัสผ่านเดิมยังใช้ได้ตามปกติ\n",
+		English: "Hello %s,\n\n" +
+			"We received a request to reset the password for your account. Open the link below within 1 hour:\n\n" +
+			"%s\n\n" +
+			"If you didn't ask for this, you can ignore this email. Your current password still works.\n",
+	},
+	"mail.forced_reset.subject": {
+		Thai:    "กรุณาตั้งรหัสผ่านใหม่ - Space Book Store",
+		English: "Please set a new password - Space Book Store",
+	},
+	"mail.forced_reset.body": {
+		Thai: "สวัสดีคุณ %s\n\n" +
+			"ผู้ดูแลระบบได้ยกเลิกรหัสผ่านเดิมของบัญชีคุณ และออกจากระบบทุกเครื่องแล้ว กดลิงก์ด้านล่างภายใน 1 ชั่วโมงเพื่อตั้งรหัสผ่านใหม่:\n\n" +
+			"%s\n\n" +
+			"ถ้าลิงก์หมดอายุ ขอลิงก์ใหม่ได้จากหน้าลืมรหัสผ่าน\n",
+		English: "Hello %s,\n\n" +
+			"An administrator has invalidated the password for your account and signed you out everywhere. Open the link below within 1 hour to set a new one:\n\n" +
+			"%s\n\n" +
+			"If the link expires, you can request a new one from the forgot-password page.\n",
+	},
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Log: Mailer สำหรับ Development ไม่ส่งอีเมลจริง
+// ต่อท้ายอีเมลลงไฟล์ที่กำหนด หรือพิมพ์ลง Log ถ้าไม่ได้กำหนดไฟล์
+type Log struct {
+	path string
+
+	mu sync.Mutex
+}
+
+// NewLog: สร้าง Mailer ที่เขียนอีเมลลงไฟล์ path (ค่าว่าง = พิมพ์ลง Log)
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+func (m *Log) Send(_ context.Context, msg Message) error {
+	entry := fmt.Sprintf("----- %s -----\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+
+	if m.path == "" {
+		log.Printf("📧 อีเมล (ไม่ได้ส่งจริง)\n%s", entry)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(entry); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,52 @@
+// Package mail: ตัวกลางส่งอีเมลของระบบ (เช่น ลิงก์รีเซ็ตรหัสผ่าน)
+// Handler คุยกับ Mailer ผ่าน Interface เท่านั้น จึงสลับระหว่าง SMTP จริงกับไฟล์/Log ได้โดยไม่ต้องแก้ Handler
+package mail
+
+import (
+	"context"
+	"fmt"
+)
+
+// ชื่อของผู้ให้บริการส่งอีเมล
+const (
+	ProviderLog  = "log"
+	ProviderSMTP = "smtp"
+)
+
+// Message: อีเมลหนึ่งฉบับ (ข้อความล้วน)
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer: สิ่งที่ผู้ให้บริการส่งอีเมลทุกแบบต้องทำได้
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config: ค่าการตั้งค่าของ Mailer (อ่านมาจาก .env ใน main.go)
+type Config struct {
+	Provider string // "log" (ค่าเริ่มต้น) หรือ "smtp"
+	From     string // ผู้ส่ง
+	LogFile  string // Provider "log": ไฟล์ที่ต่อท้ายอีเมล (ค่าว่าง = พิมพ์ลง Log)
+	SMTPHost string
+	SMTPPort string
+	SMTPUser string
+	SMTPPass string
+}
+
+// New: สร้าง Mailer ตามผู้ให้บริการ (ค่าว่างหมายถึง "log")
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Provider {
+	case "", ProviderLog:
+		return NewLog(cfg.LogFile), nil
+	case ProviderSMTP:
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			return nil, fmt.Errorf("mail: smtp provider needs SMTP_HOST and MAIL_FROM")
+		}
+		return NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("mail: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP: ส่งอีเมลจริงผ่านเซิร์ฟเวอร์ SMTP (ใช้ STARTTLS อัตโนมัติถ้าเซิร์ฟเวอร์รองรับ)
+type SMTP struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP: สร้าง Mailer แบบ SMTP (port ว่าง = 587, user ว่าง = ไม่ยืนยันตัวตน)
+func NewSMTP(host, port, user, password, from string) *SMTP {
+	if port == "" {
+		port = "587"
+	}
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+	return &SMTP{addr: net.JoinHostPort(host, port), host: host, auth: auth, from: from}
+}
+
+func (m *SMTP) Send(ctx context.Context, msg Message) error {
+	// smtp.SendMail ไม่รับ Context จึงทำงานใน Goroutine แล้วรอพร้อมกับ ctx
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, m.build(msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// build: ประกอบอีเมลตามรูปแบบ RFC 5322 (หัวเรื่องเข้ารหัสเพื่อรองรับภาษาไทย)
+func (m *SMTP) build(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + m.from + "\r\n")
+	b.WriteString("To: " + headerValue(msg.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue: ตัดการขึ้นบรรทัดใหม่ออก กันการแทรก Header (Header Injection)
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
@@ -1,17 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
-	jwtware "github.com/gofiber/contrib/jwt"
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/joho/godotenv"
 
-	"my-fiber-app/database" // เชื่อมต่อฐานข้อมูล
-	"my-fiber-app/handlers" // จัดการ API
+	"my-fiber-app/database"   // เชื่อมต่อฐานข้อมูล
+	"my-fiber-app/handlers"   // จัดการ API
+	"my-fiber-app/mail"       // ส่งอีเมล (ลิงก์รีเซ็ตรหัสผ่าน)
+	"my-fiber-app/payments"   // ผู้ให้บริการรับชำระเงิน
+	"my-fiber-app/repository" // ที่เก็บข้อมูลที่ Handler ใช้งาน
 )
 
 func main() {
@@ -20,66 +22,72 @@ func main() {
 		log.Println("คำเตือน: ไม่พบไฟล์ .env ระบบจะใช้ค่าเริ่มต้นแทน")
 	}
 
-	// 2. เชื่อมต่อฐานข้อมูล (PostgreSQL) และ Migrate ตาราง
+	// คำสั่งย่อย (เช่น migrate up|down|status) ทำงานแล้วจบ ไม่เปิดเซิร์ฟเวอร์
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// 2. เชื่อมต่อฐานข้อมูล (PostgreSQL หรือ SQLite ตาม DB_DRIVER) และ Migrate ตาราง
 	database.ConnectDb()
 
-	// 3. เริ่มต้นสร้างแอปพลิเคชัน Fiber
-	app := fiber.New()
+	// เลือกผู้ให้บริการรับชำระเงิน (ต้องระบุเอง ใช้ "fake" สำหรับ Development)
+	gateway, err := payments.New(os.Getenv("PAYMENT_PROVIDER"), os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// 4. ตั้งค่า Middleware ต่างๆ
-	// CORS: อนุญาตให้เว็บหน้าบ้าน (Frontend) รับส่งข้อมูลกับ API
+	// เลือกวิธีส่งอีเมล (ค่าเริ่มต้นคือ "log" เขียนลงไฟล์/Log สำหรับ Development)
+	mailer, err := mail.New(mail.Config{
+		Provider: os.Getenv("MAIL_PROVIDER"),
+		From:     os.Getenv("MAIL_FROM"),
+		LogFile:  os.Getenv("MAIL_LOG_FILE"),
+		SMTPHost: os.Getenv("SMTP_HOST"),
+		SMTPPort: os.Getenv("SMTP_PORT"),
+		SMTPUser: os.Getenv("SMTP_USERNAME"),
+		SMTPPass: os.Getenv("SMTP_PASSWORD"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ที่อยู่ของเว็บหน้าบ้าน ใช้ทั้งกับ CORS และลิงก์ในอีเมล
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:5173" // ค่าเริ่มต้นสำหรับ Development
 	}
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: frontendURL,
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-	}))
-
-	// Logger: ปริ้น Log การเรียกใช้งาน API ลงใน Terminal
-	app.Use(logger.New())
-
-	// 5. กำหนดเส้นทาง API (Routes)
-
-	// --- โซนสาธารณะ (Public): ไม่ต้องล็อกอิน ---
-	app.Get("/books", handlers.GetBooks)
-	app.Post("/signup", handlers.SignUp)
-	app.Post("/login", handlers.Login)
+	// เวลาที่ Checkout จองสต็อกไว้ให้ชำระเงิน (นาที) ก่อนคำสั่งซื้อถูกยกเลิกอัตโนมัติ
+	reservationTTL := handlers.DefaultReservationTTL
+	if raw := os.Getenv("RESERVATION_TTL_MINUTES"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes < 1 {
+			log.Fatalf("RESERVATION_TTL_MINUTES ต้องเป็นจำนวนนาทีที่มากกว่า 0: %q", raw)
+		}
+		reservationTTL = time.Duration(minutes) * time.Minute
+	}
 
-	// --- ตั้งค่าระบบตรวจสอบบัตรผ่าน (JWT Middleware) ---
-	jwtMiddleware := jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(os.Getenv("JWT_SECRET"))},
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "ไม่ได้รับอนุญาต: บัตรผ่านไม่ถูกต้องหรือหมดอายุ",
-			})
-		},
+	// 3. สร้างแอปพร้อม Middleware และ Routes ทั้งหมด (routes.go)
+	store := repository.NewGormStore(database.DB)
+	app := newApp(store, gateway, mailer, appConfig{
+		FrontendURL:    frontendURL,
+		JWTSecret:      os.Getenv("JWT_SECRET"),
+		PromptPayID:    os.Getenv("PROMPTPAY_ID"),
+		ReservationTTL: reservationTTL,
+		FakeCapture:    os.Getenv("PAYMENT_FAKE_CAPTURE") == "true",
 	})
 
-	// --- โซนหวงห้าม (Private): ต้องล็อกอินก่อนเข้าถึง ---
-
-	// กลุ่มผู้จัดการระบบ (Admin): จัดการคลังหนังสือ
-	adminApi := app.Group("/admin", jwtMiddleware)
-	adminApi.Post("/book", handlers.CreateBook)
-	adminApi.Put("/book/:id", handlers.UpdateBook)
-	adminApi.Delete("/book/:id", handlers.DeleteBook)
-
-	// กลุ่มผู้ใช้งานทั่วไป (User/API): จัดการตะกร้าสินค้า
-	userApi := app.Group("/api", jwtMiddleware)
-	userApi.Post("/cart", handlers.AddToCart)
-	userApi.Get("/cart", handlers.GetCart)
-	userApi.Put("/cart/:id", handlers.UpdateCartItem)
-	userApi.Delete("/cart/:id", handlers.DeleteCartItem)
-
-	// 6. รันเซิร์ฟเวอร์ตามพอร์ตที่กำหนด
+	// 4. รันเซิร์ฟเวอร์ตามพอร์ตที่กำหนด
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3000" // ค่าเริ่มต้นถ้าไม่ได้ระบุใน .env
 	}
 
+	// ตัวกวาดเบื้องหลัง: ยกเลิกคำสั่งซื้อที่ไม่ชำระเงินจนการจองสต็อกหมดอายุ และปล่อยสต็อกคืน
+	go handlers.RunReservationSweeper(context.Background(), store, time.Minute)
+
 	log.Printf("🚀 เซิร์ฟเวอร์กำลังทำงานที่พอร์ต %s", port)
 	log.Fatal(app.Listen(":" + port))
 }
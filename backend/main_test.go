@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"my-fiber-app/database"
+	"my-fiber-app/mail"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm/logger"
+)
+
+// discardMailer: Mailer ที่ไม่ส่งอะไรเลย
+type discardMailer struct{}
+
+func (discardMailer) Send(context.Context, mail.Message) error { return nil }
+
+// integrationJWTSecret: JWT Secret ที่ส่งเข้า newApp ผ่าน appConfig
+const integrationJWTSecret = "integration-secret"
+
+// integrationEnv: แอปตัวเดียวกับเซิร์ฟเวอร์จริง (newApp) บน SQLite ":memory:" ที่รัน Migration แล้ว
+type integrationEnv struct {
+	t       *testing.T
+	store   repository.Store
+	gateway *payments.Fake
+	app     *fiber.App
+}
+
+func newIntegrationEnv(t *testing.T) *integrationEnv {
+	t.Helper()
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_NAME", ":memory:")
+	// JWT_SECRET ใน Environment ต่างจาก appConfig.JWTSecret โดยตั้งใจ: แอปต้องใช้ค่าจาก appConfig เท่านั้น
+	t.Setenv("JWT_SECRET", "env-secret-must-not-be-used")
+
+	db, err := database.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := database.SeedRoles(db); err != nil {
+		t.Fatalf("seed roles: %v", err)
+	}
+
+	store := repository.NewGormStore(db)
+	gateway := payments.NewFake("whsec_integration")
+	app := newApp(store, gateway, discardMailer{}, appConfig{
+		FrontendURL:    "http://frontend.test",
+		JWTSecret:      "integration-secret",
+		ReservationTTL: 15 * time.Minute,
+	})
+	return &integrationEnv{t: t, store: store, gateway: gateway, app: app}
+}
+
+// request: ส่ง JSON เข้าแอปแล้วคืน Status และ Body (Object หรือ Array)
+func (e *integrationEnv) request(method, path string, body interface{}, headers map[string]string) (int, interface{}) {
+	e.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			e.t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.app.Test(req, -1)
+	if err != nil {
+		e.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var out interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && err != io.EOF {
+		e.t.Fatalf("%s %s: decode: %v", method, path, err)
+	}
+	return resp.StatusCode, out
+}
+
+// must: ส่ง Request แล้วตรวจว่าได้ Status ที่ต้องการ คืน Body แบบ Object
+func (e *integrationEnv) must(want int, method, path string, body interface{}, headers map[string]string) map[string]interface{} {
+	e.t.Helper()
+	status, out := e.request(method, path, body, headers)
+	if status != want {
+		e.t.Fatalf("%s %s: status %d, want %d (body %v)", method, path, status, want, out)
+	}
+	obj, _ := out.(map[string]interface{})
+	return obj
+}
+
+func bearer(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+// TestSignupCartCheckoutOnSQLite: สมัคร → เข้าสู่ระบบ → ผู้ดูแลเพิ่มหนังสือ → ใส่ตะกร้า → สั่งซื้อ → ชำระเงิน
+func TestSignupCartCheckoutOnSQLite(t *testing.T) {
+	env := newIntegrationEnv(t)
+	ctx := context.Background()
+
+	// 1. สมัครสมาชิกสองบัญชี คนหนึ่งถูกตั้งเป็นผู้ดูแล
+	for _, email := range []string{"admin@example.com", "ann@example.com"} {
+		env.must(http.StatusOK, "POST", "/signup", fiber.Map{"name": "Test", "email": email, "password": "password1"}, nil)
+	}
+	admin, err := env.store.Users().GetByEmail(ctx, "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.store.Users().UpdateRole(ctx, admin.ID, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	login := func(email string) string {
+		body := env.must(http.StatusOK, "POST", "/login", fiber.Map{"email": email, "password": "password1"}, nil)
+		return body["token"].(string)
+	}
+	adminToken, userToken := login("admin@example.com"), login("ann@example.com")
+
+	// 2. ผู้ใช้ทั่วไปเพิ่มหนังสือไม่ได้ ผู้ดูแลเพิ่มได้พร้อมสต็อกตั้งต้น
+	newBook := fiber.Map{"title": "Go in Action", "price": 300, "stock": 5}
+	env.must(http.StatusForbidden, "POST", "/admin/book", newBook, bearer(userToken))
+	book := env.must(http.StatusOK, "POST", "/admin/book", newBook, bearer(adminToken))
+	bookID := uint(book["ID"].(float64))
+
+	// 3. ใส่ตะกร้าเกินสต็อกไม่ได้ ใส่ตามจำนวนที่มีได้
+	env.must(http.StatusConflict, "POST", "/api/cart", fiber.Map{"book_id": bookID, "quantity": 6}, bearer(userToken))
+	env.must(http.StatusOK, "POST", "/api/cart", fiber.Map{"book_id": bookID, "quantity": 2}, bearer(userToken))
+	if status, cart := env.request("GET", "/api/cart", nil, bearer(userToken)); status != http.StatusOK || len(cart.([]interface{})) != 1 {
+		t.Fatalf("cart: status %d, body %v", status, cart)
+	}
+
+	// 4. สั่งซื้อ: ได้คำสั่งซื้อที่รอชำระเงิน ตะกร้าว่าง และที่ขายได้ลดลงตามยอดจอง
+	order := env.must(http.StatusCreated, "POST", "/api/checkout", nil, bearer(userToken))
+	orderID := uint(order["ID"].(float64))
+	if order["status"] != models.OrderStatusPendingPayment || order["total"] != float64(600) {
+		t.Fatalf("order = %v, want pending_payment with total 600", order)
+	}
+	if _, cart := env.request("GET", "/api/cart", nil, bearer(userToken)); len(cart.([]interface{})) != 0 {
+		t.Fatalf("cart after checkout = %v, want empty", cart)
+	}
+	view := env.must(http.StatusOK, "GET", fmt.Sprintf("/books/%d", bookID), nil, nil)
+	if view["stock"] != float64(5) || view["available"] != float64(3) {
+		t.Fatalf("book = %v, want stock 5 and available 3", view)
+	}
+
+	// 5. ชำระเงินผ่าน Gateway จำลองและ Webhook ที่เซ็นแล้ว
+	payment := env.must(http.StatusCreated, "POST", fmt.Sprintf("/api/orders/%d/pay", orderID), nil, bearer(userToken))
+	intentID := payment["intent_id"].(string)
+	if _, err := env.gateway.Capture(ctx, intentID); err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, err := env.gateway.SignedEvent(payments.EventPaymentSucceeded, intentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.must(http.StatusOK, "POST", "/payments/webhook", payload, map[string]string{payments.SignatureHeader: signature})
+
+	// 6. คำสั่งซื้อชำระแล้ว สต็อกถูกตัดจริง และสมุดบัญชีสต็อกตรงกับสต็อก
+	paid := env.must(http.StatusOK, "GET", fmt.Sprintf("/api/orders/%d", orderID), nil, bearer(userToken))
+	if paid["status"] != models.OrderStatusPaid {
+		t.Fatalf("order status = %v, want paid", paid["status"])
+	}
+	inventory := env.must(http.StatusOK, "GET", fmt.Sprintf("/admin/inventory/books/%d", bookID), nil, bearer(adminToken))
+	if inventory["stock"] != float64(3) || inventory["ledger_total"] != float64(3) || inventory["reserved"] != float64(0) {
+		t.Fatalf("inventory = %v, want stock 3, ledger_total 3, reserved 0", inventory)
+	}
+}
+
+// TestConfiguredJWTSecretSignsAndVerifies: Secret จาก appConfig (ไม่ใช่ JWT_SECRET) ใช้ทั้งเซ็นและตรวจ
+// Access Token และ Token ของตะกร้า Guest ที่ถูกรวมตอนเข้าสู่ระบบ
+func TestConfiguredJWTSecretSignsAndVerifies(t *testing.T) {
+	env := newIntegrationEnv(t)
+	ctx := context.Background()
+	book := &models.Book{Title: "Go in Action", Price: 300, Stock: 2}
+	if err := env.store.Books().Create(ctx, book); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1. ใส่ตะกร้า Guest แล้วสมัครและเข้าสู่ระบบพร้อม Token ของตะกร้า
+	guest := env.must(http.StatusOK, "POST", "/guest-cart", fiber.Map{"book_id": book.ID, "quantity": 1}, nil)
+	guestHeader := map[string]string{"X-Guest-Cart": guest["guest_token"].(string)}
+	env.must(http.StatusOK, "POST", "/signup", fiber.Map{"name": "Ann", "email": "ann@example.com", "password": "password1"}, nil)
+	login := env.must(http.StatusOK, "POST", "/login", fiber.Map{"email": "ann@example.com", "password": "password1"}, guestHeader)
+	token := login["token"].(string)
+
+	// 2. Access Token ที่ได้ใช้เรียก API ที่ต้องเข้าสู่ระบบได้ และตะกร้า Guest ถูกรวมเข้ามาแล้ว
+	status, cart := env.request("GET", "/api/cart", nil, bearer(token))
+	if status != http.StatusOK || len(cart.([]interface{})) != 1 {
+		t.Fatalf("cart: status %d, body %v, want 200 with the merged guest item", status, cart)
+	}
+
+	// 3. Token ถูกเซ็นด้วย Secret จาก appConfig ไม่ใช่ค่าใน Environment
+	keyFor := func(secret string) jwt.Keyfunc {
+		return func(*jwt.Token) (interface{}, error) { return []byte(secret), nil }
+	}
+	if _, err := jwt.Parse(token, keyFor(integrationJWTSecret)); err != nil {
+		t.Fatalf("token does not verify with the configured secret: %v", err)
+	}
+	if _, err := jwt.Parse(token, keyFor(os.Getenv("JWT_SECRET"))); err == nil {
+		t.Fatal("token verifies with JWT_SECRET from the environment, want only the configured secret")
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ActiveUser: ตรวจว่าเจ้าของ Token ยังใช้บัญชีได้ ต้องวางไว้หลัง JWT Middleware
+// บัญชีที่ถูกระงับ (หรือถูกลบ) ถูกปฏิเสธทันทีแม้ Token จะยังไม่หมดอายุ
+// และใช้ภาษาที่ผู้ใช้บันทึกไว้ (users.language) แทน Accept-Language
+//
+// อ่านจากฐานข้อมูลทุกครั้งเหมือนบทบาท ระงับบัญชีหรือเปลี่ยนภาษาแล้วมีผลทันทีโดยไม่ต้องขอ Token ใหม่
+func ActiveUser(users repository.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// 1. ดึง User ID จาก Token ที่ JWT Middleware ตรวจสอบแล้ว
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+
+		// 2. อ่านสถานะปัจจุบันของบัญชีจากฐานข้อมูล
+		user, err := users.Get(c.UserContext(), uint(userID))
+		if errors.Is(err, repository.ErrNotFound) {
+			return apperr.ErrUnauthorized.Wrap(err)
+		}
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		if user.Suspended() {
+			return apperr.ErrAccountSuspended
+		}
+
+		// 3. ตอบเป็นภาษาที่ผู้ใช้ตั้งไว้ (ถ้ามี)
+		i18n.SetPreferred(c, user.Language)
+		return c.Next()
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"my-fiber-app/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language: เลือกภาษาของคำตอบจาก Header Accept-Language (ไม่ระบุหรือไม่รองรับ = ภาษาไทย)
+func Language() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		i18n.Set(c, i18n.Negotiate(c))
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
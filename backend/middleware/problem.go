@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentTypeProblem: ชนิดเนื้อหาของคำตอบข้อผิดพลาดตาม RFC 7807
+const ContentTypeProblem = "application/problem+json"
+
+// RequestID: รหัสของ Request ปัจจุบัน (ตั้งโดย requestid Middleware และส่งกลับใน Header X-Request-ID)
+func RequestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("requestid").(string); ok {
+		return id
+	}
+	return c.GetRespHeader(fiber.HeaderXRequestID)
+}
+
+// ErrorHandler: ErrorHandler กลางของ Fiber (fiber.Config.ErrorHandler)
+// แปลง Error ทุกชนิดที่ Handler/Middleware คืนมาเป็นคำตอบ application/problem+json ที่มีรูปแบบเดียวกัน
+//
+//	{"type":"about:blank","title":"Not Found","status":404,"detail":"ไม่พบหนังสือที่ต้องการ",
+//	 "instance":"/books/99","code":"book_not_found","request_id":"..."}
+//
+// detail แปลตามภาษาของ Request (Key "error.<code>" ในแคตตาล็อก i18n)
+// ข้อมูลเพิ่มเติมของปัญหา (เช่น fields, lines) อยู่ระดับเดียวกับ Member มาตรฐาน
+// ปัญหาฝั่ง Server (5xx) จะเขียน Error ต้นเหตุลง Log พร้อม request_id แต่ไม่ส่งรายละเอียดให้ Client
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	appErr := apperr.From(err)
+	requestID := RequestID(c)
+
+	if appErr.Status >= fiber.StatusInternalServerError {
+		log.Printf("request %s: %s %s: %v", requestID, c.Method(), c.OriginalURL(), err)
+	}
+
+	problem := fiber.Map{}
+	for key, value := range appErr.Extensions {
+		problem[key] = value
+	}
+	problem["type"] = "about:blank" // ใช้ code แยกชนิดของปัญหาแทน URI
+	problem["title"] = http.StatusText(appErr.Status)
+	problem["status"] = appErr.Status
+	problem["detail"] = detail(c, appErr)
+	problem["instance"] = c.Path()
+	problem["code"] = appErr.Code
+	problem["request_id"] = requestID
+
+	return c.Status(appErr.Status).JSON(problem, ContentTypeProblem)
+}
+
+// detail: ข้อความของปัญหาในภาษาของ Request
+// รหัสที่ไม่มีในแคตตาล็อก (Error ของ Fiber เอง เช่น 405) ใช้ข้อความเดิมของ Error
+func detail(c *fiber.Ctx, appErr *apperr.Error) string {
+	key := "error." + appErr.Code
+	if !i18n.Has(key) && appErr.Message != "" {
+		return appErr.Message
+	}
+	return i18n.T(i18n.From(c), key)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"my-fiber-app/apperr"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequirePermission: Middleware ตรวจสอบสิทธิ์ของผู้ใช้ก่อนเข้าถึง Route
+// ต้องวางไว้หลัง JWT Middleware เสมอ
+//
+// บทบาทจะถูกอ่านจากฐานข้อมูลใหม่ทุกครั้ง (ไม่เชื่อ claim "role" ใน Token)
+// เพื่อให้ผู้ใช้ที่ถูกลดสิทธิ์เสียสิทธิ์ทันที โดยไม่ต้องรอ Token หมดอายุ
+func RequirePermission(users repository.UserRepository, permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// 1. ดึง User ID จาก Token ที่ JWT Middleware ตรวจสอบแล้ว
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+
+		// 2. อ่านบทบาทปัจจุบันของผู้ใช้จากฐานข้อมูล
+		user, err := users.Get(c.UserContext(), uint(userID))
+		if err != nil {
+			return apperr.ErrUnauthorized.Wrap(err)
+		}
+
+		role, err := users.GetRole(c.UserContext(), user.Role)
+		if err != nil {
+			return apperr.ErrForbidden.Wrap(err)
+		}
+
+		// 3. ตรวจสอบว่าบทบาทนี้มีสิทธิ์ที่ต้องการ
+		if !role.HasPermission(permission) {
+			return apperr.ErrForbidden.With("permission", permission)
+		}
+
+		c.Locals("role", role.Name)
+		return c.Next()
+	}
+}
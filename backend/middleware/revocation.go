@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"math"
+	"time"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RejectRevoked: ใช้เป็น SuccessHandler ของ JWT Middleware
+// ปฏิเสธ Token ที่ถูกเพิกถอนแล้ว (ออกจากระบบ) แม้ลายเซ็นและวันหมดอายุจะยังถูกต้อง
+func RejectRevoked(revocations repository.RevocationRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+
+		// Token รุ่นเก่าที่ไม่มี jti/iat เพิกถอนไม่ได้ จึงไม่รับ (ให้เข้าสู่ระบบใหม่)
+		jti, _ := claims["jti"].(string)
+		userID, hasUser := claims["user_id"].(float64)
+		iat, hasIat := claims["iat"].(float64)
+		if jti == "" || !hasUser || !hasIat {
+			return apperr.ErrUnauthorized
+		}
+
+		issuedAt := time.UnixMilli(int64(math.Round(iat * 1000)))
+		revoked, err := revocations.IsRevoked(c.UserContext(), jti, uint(userID), issuedAt)
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		if revoked {
+			return apperr.ErrTokenRevoked
+		}
+		return c.Next()
+	}
+}
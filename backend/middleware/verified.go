@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"my-fiber-app/apperr"
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequireVerifiedEmail: Middleware กั้น Route (เช่น Checkout) ไม่ให้ผู้ใช้ที่ยังไม่ยืนยันอีเมลใช้งาน
+// ทำงานเฉพาะเมื่อผู้ดูแลเปิดการตั้งค่า checkout.require_verified_email ไว้ ต้องวางไว้หลัง JWT Middleware
+func RequireVerifiedEmail(users repository.UserRepository, settings repository.SettingRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// 1. ตรวจว่าผู้ดูแลเปิดการบังคับยืนยันอีเมลไว้หรือไม่
+		required, err := repository.SettingBool(c.UserContext(), settings, models.SettingCheckoutRequiresVerifiedEmail)
+		if err != nil {
+			return apperr.ErrInternal.Wrap(err)
+		}
+		if !required {
+			return c.Next()
+		}
+
+		// 2. อ่านสถานะการยืนยันอีเมลจากฐานข้อมูล (ยืนยันแล้วใช้ได้ทันทีโดยไม่ต้องขอ Token ใหม่)
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return apperr.ErrUnauthorized
+		}
+		userID, _ := claims["user_id"].(float64)
+		user, err := users.Get(c.UserContext(), uint(userID))
+		if err != nil {
+			return apperr.ErrUnauthorized.Wrap(err)
+		}
+		if !user.EmailVerified() {
+			return apperr.ErrEmailNotVerified
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"my-fiber-app/models"
+	"my-fiber-app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestRequireVerifiedEmailWithoutJWT: วางไว้โดยไม่มี JWT Middleware ข้างหน้าต้องได้ 401 ไม่ใช่ Panic
+func TestRequireVerifiedEmailWithoutJWT(t *testing.T) {
+	store := repository.NewMemoryStore()
+	if err := store.Settings().Set(context.Background(), models.SettingCheckoutRequiresVerifiedEmail, "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	// ไม่ใส่ recover: ถ้า Middleware Panic การทดสอบจะล้มทันที
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/checkout", RequireVerifiedEmail(store.Users(), store.Settings()), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusCreated)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/checkout", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
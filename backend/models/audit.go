@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ชนิดของการกระทำของผู้ดูแลที่ถูกบันทึกใน AuditLog
+const (
+	AuditUserCreated         = "user.created"
+	AuditUserRoleChanged     = "user.role_changed"
+	AuditUserSuspended       = "user.suspended"
+	AuditUserUnsuspended     = "user.unsuspended"
+	AuditUserPasswordReset   = "user.password_reset_forced"
+	AuditUserSessionsRevoked = "user.sessions_revoked"
+)
+
+// AuditActorCLI: ActorID ของการกระทำจากคำสั่ง admin ของไบนารี (ไม่มีผู้ดูแลที่เข้าสู่ระบบ)
+const AuditActorCLI = 0
+
+// ชนิดของสิ่งที่ถูกกระทำ
+const (
+	AuditTargetUser = "user"
+)
+
+// AuditLog: บันทึกการกระทำของผู้ดูแลหนึ่งครั้ง (เพิ่มได้อย่างเดียว ไม่มีการแก้ไขหรือลบ)
+// บันทึกใน Transaction เดียวกับการกระทำ ถ้าบันทึกไม่สำเร็จการกระทำก็ไม่เกิด
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt  time.Time `json:"created_at"`
+	ActorID    uint      `json:"actor_id" gorm:"not null;index"` // AuditActorCLI = ทำผ่าน Command line
+	Action     string    `json:"action" gorm:"not null;index"`
+	TargetType string    `json:"target_type" gorm:"not null"`
+	TargetID   uint      `json:"target_id" gorm:"not null"`
+	FromValue  string    `json:"from,omitempty"` // ค่าก่อนเปลี่ยน (เช่น บทบาทเดิม)
+	ToValue    string    `json:"to,omitempty"`   // ค่าหลังเปลี่ยน
+	Reason     string    `json:"reason,omitempty"`
+	RequestID  string    `json:"request_id"` // จับคู่กับ Log ของเซิร์ฟเวอร์
+}
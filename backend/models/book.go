@@ -4,11 +4,11 @@ import "gorm.io/gorm"
 
 // ชื่อ Struct ต้องตัวใหญ่ (Book) เพื่อให้ไฟล์อื่นเรียกใช้ได้
 type Book struct {
-    gorm.Model
-    Title  string `json:"title" validate:"required,min=3"`
-    Author string `json:"author"`
-    Price  int    `json:"price" validate:"required,gte=0"`
-    ImageURL string `json:"image_url"`
-    Stock    int    `json:"stock" gorm:"default:0"`
-    Description string `json:"description"`
-}
\ No newline at end of file
+	gorm.Model
+	Title       string `json:"title" validate:"required,min=3,max=255" gorm:"index"`
+	Author      string `json:"author" validate:"max=255"`
+	Price       int    `json:"price" validate:"gte=0" gorm:"index"`
+	ImageURL    string `json:"image_url" validate:"max=2048"`
+	Stock       int    `json:"stock" validate:"gte=0" gorm:"default:0"`
+	Description string `json:"description" validate:"max=5000"`
+}
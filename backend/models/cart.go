@@ -3,9 +3,9 @@ package models
 import "gorm.io/gorm"
 
 type CartItem struct {
-    gorm.Model
-    UserID   uint   `json:"user_id" gorm:"not null"`
-    BookID   uint   `json:"book_id" gorm:"not null"`
-    Book     Book   `json:"book" gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"` 
-    Quantity int    `json:"quantity" gorm:"default:1"`
-}
\ No newline at end of file
+	gorm.Model
+	UserID   uint `json:"user_id" gorm:"not null;uniqueIndex:idx_cart_items_user_book"` // หนึ่งรายการต่อหนังสือหนึ่งเล่ม
+	BookID   uint `json:"book_id" gorm:"not null;uniqueIndex:idx_cart_items_user_book"`
+	Book     Book `json:"book" gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Quantity int  `json:"quantity" gorm:"default:1"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// GuestCart: ตะกร้าของผู้ที่ยังไม่ได้เข้าสู่ระบบ อ้างอิงด้วย ID สุ่มที่ Client ถือไว้ในรูปแบบที่เซ็นแล้ว
+// (Cookie หรือ Header X-Guest-Cart) เมื่อเข้าสู่ระบบหรือสมัครสมาชิก รายการจะถูกรวมเข้าตะกร้าของผู้ใช้แล้วลบทิ้ง
+type GuestCart struct {
+	ID        string          `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"` // ใช้งานล่าสุด ตะกร้าที่ไม่ได้ใช้นานเกินกำหนดจะถูกลบ
+	Items     []GuestCartItem `json:"items,omitempty"`
+}
+
+// GuestCartItem: หนังสือหนึ่งเล่มในตะกร้าของ Guest (หนึ่งแถวต่อหนังสือหนึ่งเล่ม ลบแล้วหายจริง)
+type GuestCartItem struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	GuestCartID string    `json:"-" gorm:"not null;uniqueIndex:idx_guest_cart_items_cart_book"`
+	BookID      uint      `json:"book_id" gorm:"not null;uniqueIndex:idx_guest_cart_items_cart_book"`
+	Book        Book      `json:"book" gorm:"foreignKey:BookID"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ชนิดของการเคลื่อนไหวของสต็อก
+const (
+	MovementRestock    = "restock"    // รับของเข้า
+	MovementSale       = "sale"       // ขายออก (คำสั่งซื้อที่ชำระเงินแล้ว หรือขายนอกระบบ)
+	MovementReturn     = "return"     // ของกลับเข้าสต็อก (คืนเงินก่อนส่งของ หรือลูกค้าส่งคืน)
+	MovementAdjustment = "adjustment" // ปรับยอดให้ตรงกับของจริง (บวกหรือลบก็ได้)
+	MovementDamage     = "damage"     // ของเสียหาย ขายไม่ได้
+)
+
+// MovementTypes: ชนิดทั้งหมดที่ระบบรู้จัก
+var MovementTypes = []string{MovementRestock, MovementSale, MovementReturn, MovementAdjustment, MovementDamage}
+
+// MovementSign: ทิศทางของจำนวนตามชนิด (+1 เพิ่มสต็อก, -1 ลดสต็อก, 0 = ระบุเครื่องหมายเอง)
+func MovementSign(movementType string) int {
+	switch movementType {
+	case MovementRestock, MovementReturn:
+		return 1
+	case MovementSale, MovementDamage:
+		return -1
+	}
+	return 0
+}
+
+// StockMovement: บันทึกการเปลี่ยนสต็อกหนึ่งครั้ง (เพิ่มได้อย่างเดียว ไม่มีการแก้ไขหรือลบ)
+// Book.Stock เท่ากับผลรวมของ Quantity ทุกแถวของเล่มนั้นเสมอ เพราะสต็อกเปลี่ยนผ่านการบันทึกนี้เท่านั้น
+type StockMovement struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt  time.Time `json:"created_at"`
+	BookID     uint      `json:"book_id" gorm:"not null;index"`
+	Type       string    `json:"type" gorm:"not null;index"`
+	Quantity   int       `json:"quantity" gorm:"not null"`    // บวก = เข้า, ลบ = ออก
+	StockAfter int       `json:"stock_after" gorm:"not null"` // สต็อกหลังการเคลื่อนไหวนี้
+	ActorID    *uint     `json:"actor_id"`                    // nil = ระบบเป็นผู้บันทึก (เช่น Webhook การชำระเงิน)
+	OrderID    *uint     `json:"order_id,omitempty" gorm:"index"`
+	Reason     string    `json:"reason"`
+}
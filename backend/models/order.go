@@ -0,0 +1,80 @@
+package models
+
+import "gorm.io/gorm"
+
+// สถานะของคำสั่งซื้อ (Order Lifecycle)
+const (
+	OrderStatusPendingPayment = "pending_payment" // สร้างจากตะกร้าแล้ว รอชำระเงิน
+	OrderStatusPaid           = "paid"            // ชำระเงินแล้ว
+	OrderStatusPacked         = "packed"          // แพ็กสินค้าแล้ว
+	OrderStatusShipped        = "shipped"         // ส่งของแล้ว
+	OrderStatusDelivered      = "delivered"       // ลูกค้าได้รับของแล้ว
+	OrderStatusCancelled      = "cancelled"       // ยกเลิกก่อนชำระเงิน
+	OrderStatusRefunded       = "refunded"        // คืนเงินแล้ว
+)
+
+// orderTransitions: ตารางการเปลี่ยนสถานะที่อนุญาต (สถานะปัจจุบัน -> สถานะถัดไปที่ทำได้)
+// สถานะที่ไม่มีในตารางถือว่าเป็นสถานะสุดท้าย
+var orderTransitions = map[string][]string{
+	OrderStatusPendingPayment: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:           {OrderStatusPacked, OrderStatusRefunded},
+	OrderStatusPacked:         {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:        {OrderStatusDelivered},
+	OrderStatusDelivered:      {OrderStatusRefunded},
+}
+
+// AllowedTransitions: สถานะถัดไปที่คำสั่งซื้อในสถานะ from เปลี่ยนไปได้
+func AllowedTransitions(from string) []string {
+	return orderTransitions[from]
+}
+
+// CanTransition: ตรวจสอบว่าเปลี่ยนจากสถานะ from ไปเป็น to ได้หรือไม่
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleasesStock: การเปลี่ยนสถานะนี้ต้องคืนสต็อกหรือไม่
+// (คืนเงินหลังตัดสต็อกแล้วแต่ของยังไม่ถูกส่งออกไป คำสั่งซื้อที่รอชำระเงินยังไม่ได้ตัดสต็อก มีแค่การจอง)
+func ReleasesStock(from, to string) bool {
+	if to != OrderStatusCancelled && to != OrderStatusRefunded {
+		return false
+	}
+	return from == OrderStatusPaid || from == OrderStatusPacked
+}
+
+// Order: คำสั่งซื้อที่สร้างจากตะกร้าสินค้าของผู้ใช้
+type Order struct {
+	gorm.Model
+	UserID      uint              `json:"user_id" gorm:"not null;index"`
+	Status      string            `json:"status" gorm:"not null;default:'pending_payment'"`
+	Total       int               `json:"total" gorm:"not null"`
+	Items       []OrderItem       `json:"items" gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Transitions []OrderTransition `json:"transitions,omitempty" gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// OrderItem: รายการสินค้าในคำสั่งซื้อ
+// เก็บชื่อและราคา ณ เวลาที่สั่งซื้อไว้ เพื่อไม่ให้การแก้ไขหนังสือภายหลังกระทบใบเสร็จเดิม
+type OrderItem struct {
+	gorm.Model
+	OrderID  uint   `json:"order_id" gorm:"not null;index"`
+	BookID   uint   `json:"book_id" gorm:"not null"`
+	Title    string `json:"title" gorm:"not null"`
+	Price    int    `json:"price" gorm:"not null"`
+	Quantity int    `json:"quantity" gorm:"not null"`
+}
+
+// OrderTransition: ประวัติการเปลี่ยนสถานะของคำสั่งซื้อ (ใคร เมื่อไร เพราะอะไร)
+// ActorID เป็น nil เมื่อระบบเป็นผู้เปลี่ยนเอง เช่น จาก Webhook การชำระเงิน
+type OrderTransition struct {
+	gorm.Model
+	OrderID    uint   `json:"order_id" gorm:"not null;index"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status" gorm:"not null"`
+	ActorID    *uint  `json:"actor_id"`
+	Reason     string `json:"reason"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "gorm.io/gorm"
+
+// Payment: การชำระเงินของคำสั่งซื้อผ่านผู้ให้บริการ (หนึ่ง Order มีได้หลายครั้ง เช่น ลองจ่ายใหม่)
+type Payment struct {
+	gorm.Model
+	OrderID  uint   `json:"order_id" gorm:"not null;index"`
+	Provider string `json:"provider" gorm:"not null"`
+	IntentID string `json:"intent_id" gorm:"not null;uniqueIndex"`
+	Amount   int    `json:"amount" gorm:"not null"`
+	Currency string `json:"currency" gorm:"not null"`
+	Status   string `json:"status" gorm:"not null"`
+}
+
+// PaymentEvent: Webhook ที่ประมวลผลไปแล้ว
+// EventID ไม่ซ้ำกัน ใช้ตรวจจับการส่ง Webhook เดิมซ้ำ (Replay)
+type PaymentEvent struct {
+	gorm.Model
+	EventID  string `json:"event_id" gorm:"not null;uniqueIndex"`
+	Type     string `json:"type" gorm:"not null"`
+	IntentID string `json:"intent_id" gorm:"not null;index"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// StockReservation: สต็อกที่ถูกจองไว้ให้คำสั่งซื้อที่รอชำระเงิน (หนึ่งแถวต่อหนังสือหนึ่งเล่มของคำสั่งซื้อ)
+// ยังไม่ตัดจาก Book.Stock จนกว่าจะชำระเงิน จำนวนที่ขายได้จริงคือ Stock ลบยอดจองทั้งหมดของเล่มนั้น
+// ถูกลบ (ไม่ใช่ Soft Delete) เมื่อชำระเงิน ยกเลิก หรือหมดอายุ
+type StockReservation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	OrderID   uint      `json:"order_id" gorm:"not null;index"`
+	BookID    uint      `json:"book_id" gorm:"not null;index"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"` // หลังจากนี้ตัวกวาดจะยกเลิกคำสั่งซื้อและปล่อยสต็อก
+}
@@ -0,0 +1,59 @@
+package models
+
+import "gorm.io/gorm"
+
+// ชื่อบทบาทมาตรฐานของระบบ (ค่าเดียวกับที่เก็บใน User.Role)
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ชื่อสิทธิ์ (Permission) ที่ Middleware ใช้ตรวจสอบก่อนเข้าถึง Route
+const (
+	PermBooksWrite      = "books:write"      // เพิ่ม/แก้ไข/ลบ หนังสือ
+	PermInventoryManage = "inventory:manage" // บันทึกการเคลื่อนไหวของสต็อกและดูสมุดบัญชีสต็อก
+	PermOrdersManage    = "orders:manage"    // เปลี่ยนสถานะคำสั่งซื้อ
+	PermPaymentsManage  = "payments:manage"  // ยืนยันการชำระเงินที่ตรวจสอบด้วยมือ (เช่น สลิป PromptPay)
+	PermUsersManage     = "users:manage"     // จัดการบัญชีผู้ใช้ (เช่น ยกเลิกการเข้าสู่ระบบทุกเครื่อง)
+	PermSettingsManage  = "settings:manage"  // ปรับการตั้งค่าระบบ (เช่น บังคับยืนยันอีเมลก่อนสั่งซื้อ)
+)
+
+// DefaultPermissions: สิทธิ์ทั้งหมดที่ระบบรู้จัก พร้อมคำอธิบาย
+var DefaultPermissions = map[string]string{
+	PermBooksWrite:      "Create, update and delete books",
+	PermInventoryManage: "Post stock movements and view the stock ledger",
+	PermOrdersManage:    "Move orders through their lifecycle",
+	PermPaymentsManage:  "Confirm manually verified payments",
+	PermUsersManage:     "Manage user accounts and sessions",
+	PermSettingsManage:  "Change system settings",
+}
+
+// DefaultRoles: บทบาทเริ่มต้นและสิทธิ์ที่แต่ละบทบาทได้รับ
+var DefaultRoles = map[string][]string{
+	RoleUser:  {},
+	RoleAdmin: {PermBooksWrite, PermInventoryManage, PermOrdersManage, PermPaymentsManage, PermUsersManage, PermSettingsManage},
+}
+
+// Permission: สิทธิ์ย่อยแต่ละอย่างในระบบ
+type Permission struct {
+	gorm.Model
+	Name        string `gorm:"unique;not null" json:"name"`
+	Description string `json:"description"`
+}
+
+// Role: บทบาทของผู้ใช้ ผูกกับ User.Role ด้วยชื่อ และถือสิทธิ์ได้หลายอย่าง
+type Role struct {
+	gorm.Model
+	Name        string       `gorm:"unique;not null" json:"name"`
+	Permissions []Permission `gorm:"many2many:role_permissions;" json:"permissions"`
+}
+
+// HasPermission: ตรวจสอบว่าบทบาทนี้มีสิทธิ์ที่ระบุหรือไม่
+func (r *Role) HasPermission(name string) bool {
+	for _, p := range r.Permissions {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ชื่อการตั้งค่าระบบที่ผู้ดูแลปรับได้ขณะระบบทำงาน
+const (
+	// SettingCheckoutRequiresVerifiedEmail: "true" = ผู้ใช้ที่ยังไม่ยืนยันอีเมลดูสินค้าได้แต่สั่งซื้อไม่ได้
+	SettingCheckoutRequiresVerifiedEmail = "checkout.require_verified_email"
+)
+
+// DefaultSettings: การตั้งค่าทั้งหมดที่ระบบรู้จัก พร้อมค่าเริ่มต้น (ใช้เมื่อยังไม่มีแถวในตาราง settings)
+// ตอนนี้ทุกค่าเป็นแบบ Boolean ("true"/"false")
+var DefaultSettings = map[string]string{
+	SettingCheckoutRequiresVerifiedEmail: "false",
+}
+
+// Setting: ค่าการตั้งค่าระบบหนึ่งรายการ (Key/Value)
+type Setting struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	Value     string    `json:"value" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken: Refresh Token แบบทึบ (Opaque) เก็บเฉพาะค่า Hash (SHA-256) ไม่เก็บตัว Token จริง
+// Token ที่ออกต่อกันจากการ Login ครั้งเดียวอยู่ใน Family เดียวกัน
+// ถ้า Token ที่ใช้ไปแล้วถูกนำมาใช้ซ้ำ ถือว่าหลุด และทั้ง Family จะถูกเพิกถอน
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`    // เวลาที่ถูกแลกเป็น Token ใหม่ (ใช้ได้ครั้งเดียว)
+	RevokedAt *time.Time `json:"revoked_at"` // เวลาที่ถูกเพิกถอน
+}
+
+// RevokedToken: Access Token (JWT) ที่ถูกเพิกถอนก่อนหมดอายุ อ้างอิงด้วย Claim "jti"
+// เก็บไว้ถึง ExpiresAt เท่านั้น หลังจากนั้น Token หมดอายุเองอยู่แล้ว
+type RevokedToken struct {
+	gorm.Model
+	JTI       string    `json:"jti" gorm:"column:jti;not null;uniqueIndex"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// SessionRevocation: ยกเลิกทุก Access Token ของผู้ใช้ที่ออกก่อน RevokedBefore (ออกจากระบบทุกเครื่อง)
+type SessionRevocation struct {
+	UserID        uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `json:"revoked_before" gorm:"not null"`
+}
+
+// PasswordResetToken: Token สำหรับตั้งรหัสผ่านใหม่ที่ส่งทางอีเมล เก็บเฉพาะค่า Hash (SHA-256)
+// ใช้ได้ครั้งเดียวและมีอายุจำกัด
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"` // เวลาที่ถูกใช้ หรือถูกยกเลิกเพราะขอ Token ใหม่
+}
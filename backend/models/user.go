@@ -1,11 +1,29 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-    gorm.Model
-    Email    string `gorm:"unique;not null" json:"email"`
-    Password string `gorm:"not null" json:"-"` // ใส่ "-" เพื่อไม่ให้หลุดออกไปทาง API
-    Name     string `json:"name"`
-    Role     string `json:"role" gorm:"default:'user'"`
-}
\ No newline at end of file
+	gorm.Model
+	Email           string     `gorm:"unique;not null" json:"email"`
+	Password        string     `gorm:"not null" json:"-"` // ใส่ "-" เพื่อไม่ให้หลุดออกไปทาง API
+	Name            string     `json:"name"`
+	Role            string     `json:"role" gorm:"default:'user'"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`                   // nil = ยังไม่ได้ยืนยันอีเมล
+	Language        string     `json:"language" gorm:"not null;default:''"` // ภาษาของข้อความจาก API ("th", "en") ค่าว่าง = ตาม Accept-Language
+	SuspendedAt     *time.Time `json:"suspended_at"`                        // nil = ใช้งานได้ปกติ ไม่ nil = ถูกผู้ดูแลระงับบัญชี
+	SuspendReason   string     `json:"suspend_reason" gorm:"not null;default:''"`
+}
+
+// EmailVerified: ผู้ใช้ยืนยันอีเมลแล้วหรือไม่
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Suspended: บัญชีถูกระงับอยู่หรือไม่ (เข้าสู่ระบบและใช้ Token เดิมไม่ได้)
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
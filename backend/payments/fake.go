@@ -0,0 +1,133 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ProviderFake: ชื่อของผู้ให้บริการจำลอง
+const ProviderFake = "fake"
+
+// Fake: Gateway จำลองที่ทำงานในหน่วยความจำ ไม่ต้องใช้เครือข่าย
+// ผลลัพธ์คาดเดาได้ทุกครั้ง (ID เรียงตามลำดับ ลายเซ็นเป็น HMAC-SHA256 ของ Payload)
+// เหมาะสำหรับการพัฒนาและทดสอบ Checkout แบบครบวงจร
+type Fake struct {
+	secret []byte
+
+	mu        sync.Mutex
+	intents   map[string]*Intent
+	intentSeq int
+	eventSeq  int
+}
+
+// NewFake: สร้าง Gateway จำลองที่เซ็น Webhook ด้วย secret ที่ให้มา
+func NewFake(secret string) *Fake {
+	return &Fake{
+		secret:  []byte(secret),
+		intents: make(map[string]*Intent),
+	}
+}
+
+func (f *Fake) Name() string { return ProviderFake }
+
+func (f *Fake) CreateIntent(_ context.Context, orderID uint, amount int, currency string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.intentSeq++
+	intent := &Intent{
+		ID:       fmt.Sprintf("pi_fake_%d", f.intentSeq),
+		OrderID:  orderID,
+		Amount:   amount,
+		Currency: currency,
+		Status:   IntentStatusPending,
+	}
+	f.intents[intent.ID] = intent
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) Capture(_ context.Context, intentID string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentStatusPending {
+		return nil, ErrInvalidState
+	}
+	intent.Status = IntentStatusSucceeded
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) Refund(_ context.Context, intentID string, amount int) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentStatusSucceeded || amount <= 0 || amount > intent.Amount {
+		return nil, ErrInvalidState
+	}
+	intent.Status = IntentStatusRefunded
+	copied := *intent
+	return &copied, nil
+}
+
+func (f *Fake) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if !hmac.Equal([]byte(f.sign(payload)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SignedEvent: จำลองการส่ง Webhook ของผู้ให้บริการ
+// คืน Payload และลายเซ็นที่นำไปส่งให้ Endpoint Webhook ได้ทันที
+func (f *Fake) SignedEvent(eventType, intentID string) ([]byte, string, error) {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if !ok {
+		f.mu.Unlock()
+		return nil, "", ErrIntentNotFound
+	}
+	f.eventSeq++
+	event := Event{
+		ID:       fmt.Sprintf("evt_fake_%d", f.eventSeq),
+		Type:     eventType,
+		IntentID: intent.ID,
+		Amount:   intent.Amount,
+	}
+	f.mu.Unlock()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, f.sign(payload), nil
+}
+
+// Sign: ลายเซ็นของ Payload ใดๆ จำลอง Webhook ที่ผู้ให้บริการส่งข้อมูลผิดปกติมา (เช่น ยอดเงินไม่ตรงกับ Intent)
+func (f *Fake) Sign(payload []byte) string {
+	return f.sign(payload)
+}
+
+// sign: ลายเซ็น HMAC-SHA256 ของ Payload ในรูป Hex
+func (f *Fake) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
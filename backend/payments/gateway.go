@@ -0,0 +1,81 @@
+// Package payments: ตัวกลางเชื่อมต่อผู้ให้บริการรับชำระเงิน (Payment Gateway)
+// Handler จะคุยกับ Gateway ผ่าน Interface เท่านั้น จึงสลับผู้ให้บริการได้โดยไม่ต้องแก้ Handler
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// สถานะของ Payment Intent
+const (
+	IntentStatusPending   = "pending"
+	IntentStatusSucceeded = "succeeded"
+	IntentStatusRefunded  = "refunded"
+)
+
+// ชนิดของเหตุการณ์ที่ส่งมาทาง Webhook
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventPaymentRefunded  = "payment.refunded"
+)
+
+// SignatureHeader: Header ที่ผู้ให้บริการใช้ส่งลายเซ็นของ Webhook
+const SignatureHeader = "X-Payment-Signature"
+
+var (
+	ErrIntentNotFound   = errors.New("payments: intent not found")
+	ErrInvalidSignature = errors.New("payments: invalid webhook signature")
+	ErrInvalidState     = errors.New("payments: intent is not in a valid state for this operation")
+)
+
+// Intent: ความตั้งใจชำระเงินหนึ่งครั้งของคำสั่งซื้อ
+type Intent struct {
+	ID       string `json:"id"`
+	OrderID  uint   `json:"order_id"`
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"`
+}
+
+// Event: เหตุการณ์จากผู้ให้บริการที่ผ่านการตรวจลายเซ็นแล้ว
+// ID ต้องไม่ซ้ำกัน ใช้สำหรับกันการส่ง Webhook ซ้ำ (Replay)
+type Event struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+	Amount   int    `json:"amount"`
+}
+
+// Gateway: สิ่งที่ผู้ให้บริการรับชำระเงินทุกเจ้าต้องทำได้
+type Gateway interface {
+	// Name: ชื่อผู้ให้บริการ (เก็บไว้ใน Payment.Provider)
+	Name() string
+	// CreateIntent: สร้างรายการรอชำระเงินสำหรับคำสั่งซื้อ
+	CreateIntent(ctx context.Context, orderID uint, amount int, currency string) (*Intent, error)
+	// Capture: ยืนยันการตัดเงินของ Intent ที่ได้รับอนุมัติแล้ว
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	// Refund: คืนเงินตามจำนวนที่ระบุ
+	Refund(ctx context.Context, intentID string, amount int) (*Intent, error)
+	// VerifyWebhook: ตรวจลายเซ็นและแปลง Payload ของ Webhook เป็น Event
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// New: สร้าง Gateway ตามชื่อผู้ให้บริการ ต้องระบุชื่อเสมอ (ไม่มีค่าเริ่มต้น กันการเผลอใช้ "fake" บน Production)
+// และต้องมี webhookSecret เพราะ Webhook ที่ไม่มีกุญแจถูกปลอมลายเซ็นได้
+func New(provider, webhookSecret string) (Gateway, error) {
+	if provider == "" {
+		return nil, fmt.Errorf("payments: PAYMENT_PROVIDER is not set (use %q for development)", ProviderFake)
+	}
+	if webhookSecret == "" {
+		return nil, errors.New("payments: PAYMENT_WEBHOOK_SECRET is not set")
+	}
+	switch provider {
+	case ProviderFake:
+		return NewFake(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("payments: unknown provider %q", provider)
+	}
+}
@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProviderPromptPay: ชื่อผู้ให้บริการสำหรับการโอนผ่าน PromptPay (ยืนยันด้วยสลิปโดยแอดมิน)
+const ProviderPromptPay = "promptpay"
+
+// ค่าคงที่ตามมาตรฐาน EMVCo / Thai QR Payment
+const (
+	promptPayAID        = "A000000677010111" // Application ID ของ PromptPay (โอนเงิน)
+	promptPayCountry    = "TH"
+	promptPayCurrency   = "764" // รหัสสกุลเงินบาท (ISO 4217)
+	promptPayStatic     = "11"  // QR ใช้ซ้ำได้ ไม่ระบุยอดเงิน
+	promptPayDynamic    = "12"  // QR ใช้ครั้งเดียว ระบุยอดเงิน
+	promptPayPhoneTag   = "01"
+	promptPayTaxIDTag   = "02"
+	promptPayEWalletTag = "03"
+)
+
+var ErrInvalidPromptPayID = errors.New("payments: invalid PromptPay ID")
+
+// PromptPayPayload: สร้างข้อความสำหรับ QR PromptPay ตามมาตรฐาน EMVCo พร้อม CRC16
+// id เป็นเบอร์มือถือ (10 หลัก) เลขประจำตัวผู้เสียภาษี/บัตรประชาชน (13 หลัก) หรือ e-Wallet ID (15 หลัก)
+// amount เป็นยอดเงินหน่วยบาท ถ้าเป็น 0 จะได้ QR แบบไม่ระบุยอด
+func PromptPayPayload(id string, amount int) (string, error) {
+	// 1. เก็บเฉพาะตัวเลข แล้วเลือกชนิดของบัญชีตามความยาว
+	var digits strings.Builder
+	for _, r := range id {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	target := digits.String()
+
+	var account string
+	switch {
+	case len(target) == 15:
+		account = emvField(promptPayEWalletTag, target)
+	case len(target) == 13:
+		account = emvField(promptPayTaxIDTag, target)
+	case len(target) == 10 && target[0] == '0':
+		// เบอร์มือถือ: ตัด 0 ข้างหน้า ใส่รหัสประเทศ 66 และเติม 0 ด้านซ้ายให้ครบ 13 หลัก
+		account = emvField(promptPayPhoneTag, fmt.Sprintf("%013s", "66"+target[1:]))
+	default:
+		return "", ErrInvalidPromptPayID
+	}
+	if amount < 0 {
+		return "", errors.New("payments: amount must not be negative")
+	}
+
+	// 2. ประกอบ Payload ตามลำดับ Tag
+	pointOfInitiation := promptPayStatic
+	if amount > 0 {
+		pointOfInitiation = promptPayDynamic
+	}
+
+	var b strings.Builder
+	b.WriteString(emvField("00", "01")) // Payload Format Indicator
+	b.WriteString(emvField("01", pointOfInitiation))
+	b.WriteString(emvField("29", emvField("00", promptPayAID)+account))
+	b.WriteString(emvField("58", promptPayCountry))
+	b.WriteString(emvField("53", promptPayCurrency))
+	if amount > 0 {
+		b.WriteString(emvField("54", fmt.Sprintf("%d.00", amount)))
+	}
+
+	// 3. CRC คำนวณรวม Tag "63" และความยาว "04" ของตัวมันเองด้วย
+	b.WriteString("6304")
+	payload := b.String()
+	return payload + fmt.Sprintf("%04X", crc16CCITT([]byte(payload))), nil
+}
+
+// emvField: ประกอบข้อมูลรูปแบบ ID + ความยาว 2 หลัก + ค่า
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// crc16CCITT: CRC-16/CCITT-FALSE (Polynomial 0x1021, ค่าเริ่มต้น 0xFFFF) ตามที่ EMVCo กำหนด
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var _ Store = (*GormStore)(nil)
+
+// GormStore: Store ที่ใช้ GORM เชื่อมต่อฐานข้อมูลจริง
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore: สร้าง Store จาก Connection ของ GORM
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+func (s *GormStore) Books() BookRepository                   { return gormBooks{db: s.db} }
+func (s *GormStore) Users() UserRepository                   { return gormUsers{db: s.db} }
+func (s *GormStore) Carts() CartRepository                   { return gormCarts{db: s.db} }
+func (s *GormStore) GuestCarts() GuestCartRepository         { return gormGuestCarts{db: s.db} }
+func (s *GormStore) Orders() OrderRepository                 { return gormOrders{db: s.db} }
+func (s *GormStore) Reservations() ReservationRepository     { return gormReservations{db: s.db} }
+func (s *GormStore) StockMovements() StockMovementRepository { return gormStockMovements{db: s.db} }
+func (s *GormStore) Payments() PaymentRepository             { return gormPayments{db: s.db} }
+func (s *GormStore) Tokens() TokenRepository                 { return gormTokens{db: s.db} }
+func (s *GormStore) Revocations() RevocationRepository       { return gormRevocations{db: s.db} }
+func (s *GormStore) PasswordResets() PasswordResetRepository { return gormPasswordResets{db: s.db} }
+func (s *GormStore) Settings() SettingRepository             { return gormSettings{db: s.db} }
+func (s *GormStore) AuditLogs() AuditLogRepository           { return gormAuditLogs{db: s.db} }
+
+func (s *GormStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormStore{db: tx})
+	})
+}
+
+// translateError: แปลง Error ของ GORM เป็น Error ของ Package นี้
+// (ErrDuplicatedKey ต้องเปิด gorm.Config.TranslateError)
+func translateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	default:
+		return err
+	}
+}
+
+// isSQLite: ฐานข้อมูลเป็น SQLite หรือไม่ (ใช้เลือก Query ที่ PostgreSQL กับ SQLite เขียนต่างกัน)
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// timeExpr: นิพจน์ของเวลาที่ใช้เปรียบเทียบหรือเรียงลำดับได้ถูกต้องทั้งสองฐานข้อมูล
+// SQLite เก็บเวลาเป็นข้อความที่ตัดเลขศูนย์ท้ายทศนิยมทิ้ง (".12" กับ ".115") จึงเทียบเป็นข้อความไม่ได้
+// ต้องแปลงเป็นตัวเลขด้วย julianday() ก่อน (ใช้ได้ทั้งกับชื่อคอลัมน์และ "?")
+func timeExpr(db *gorm.DB, expr string) string {
+	if isSQLite(db) {
+		return "julianday(" + expr + ")"
+	}
+	return expr
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+)
+
+type gormAuditLogs struct {
+	db *gorm.DB
+}
+
+func (r gormAuditLogs) Create(ctx context.Context, entry *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r gormAuditLogs) List(ctx context.Context, f AuditLogFilter, limit, offset int) ([]models.AuditLog, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if f.ActorID != 0 {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID != 0 {
+		q = q.Where("target_id = ?", f.TargetID)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var entries []models.AuditLog
+	err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"my-fiber-app/models"
+	"my-fiber-app/search"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bookSortColumns: คอลัมน์ที่อนุญาตให้ใช้เรียง (ป้องกัน SQL Injection จากชื่อคอลัมน์)
+var bookSortColumns = map[string]bool{
+	"price":      true,
+	"title":      true,
+	"created_at": true,
+}
+
+type gormBooks struct {
+	db *gorm.DB
+}
+
+// filtered: Query ของหนังสือที่ใส่ตัวกรองแล้ว
+func (r gormBooks) filtered(ctx context.Context, f BookFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&models.Book{})
+	if f.Author != "" {
+		q = q.Where("LOWER(author) LIKE ?", "%"+strings.ToLower(f.Author)+"%")
+	}
+	if f.MinPrice != nil {
+		q = q.Where("price >= ?", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		q = q.Where("price <= ?", *f.MaxPrice)
+	}
+	if f.InStock {
+		// มีเล่มที่ขายได้ คือสต็อกมากกว่ายอดที่ถูกจองไว้
+		q = q.Where("stock > " + reservedStockSQL)
+	}
+	return q
+}
+
+func (r gormBooks) List(ctx context.Context, q BookQuery) ([]models.Book, error) {
+	if !bookSortColumns[q.SortBy] {
+		return nil, fmt.Errorf("repository: unsupported sort column %q", q.SortBy)
+	}
+
+	op, dir := ">", "ASC"
+	if q.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	db := r.filtered(ctx, q.BookFilter)
+	column, param := q.SortBy, "?"
+	if q.SortBy == "created_at" {
+		column, param = timeExpr(r.db, column), timeExpr(r.db, param)
+	}
+	if q.After != nil {
+		// Keyset: ใช้ id เป็นตัวตัดสินเมื่อค่าที่ใช้เรียงเท่ากัน ลำดับจึงคงที่เสมอ
+		db = db.Where(fmt.Sprintf("((%s %s %s) OR (%s = %s AND id %s ?))", column, op, param, column, param, op),
+			q.After.Value, q.After.Value, q.After.ID)
+	}
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+
+	var books []models.Book
+	err := db.Order(column + " " + dir).Order("id " + dir).Limit(q.Limit).Find(&books).Error
+	return books, err
+}
+
+func (r gormBooks) Count(ctx context.Context, f BookFilter) (int64, error) {
+	var total int64
+	err := r.filtered(ctx, f).Count(&total).Error
+	return total, err
+}
+
+// bookRow: ผลลัพธ์ดิบจาก SQL (หนังสือ + คะแนน)
+type bookRow struct {
+	models.Book
+	Rank float64
+}
+
+func toMatches(rows []bookRow) []BookMatch {
+	matches := make([]BookMatch, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, BookMatch{Book: row.Book, Rank: row.Rank})
+	}
+	return matches
+}
+
+func (r gormBooks) Search(ctx context.Context, terms []search.Term, limit int) ([]BookMatch, error) {
+	if isSQLite(r.db) {
+		return r.searchLike(ctx, terms, limit)
+	}
+
+	// คำภาษาไทยค้นแบบ Phrase ทีละอักษร คำอื่นค้นแบบขึ้นต้นด้วย (prefix)
+	parts := make([]string, 0, len(terms))
+	args := make([]interface{}, 0, len(terms)+1)
+	for _, t := range terms {
+		if t.Thai {
+			parts = append(parts, "phraseto_tsquery('simple', ?)")
+			args = append(args, search.SpaceThai(t.Text))
+		} else {
+			parts = append(parts, "to_tsquery('simple', ?)")
+			args = append(args, t.Text+":*")
+		}
+	}
+	args = append(args, limit)
+
+	var rows []bookRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT books.*, ts_rank_cd(books.search_vector, query.q) AS rank
+		FROM books, (SELECT `+strings.Join(parts, " && ")+` AS q) AS query
+		WHERE books.deleted_at IS NULL AND books.search_vector @@ query.q
+		ORDER BY rank DESC, books.id
+		LIMIT ?`, args...).Scan(&rows).Error
+	return toMatches(rows), err
+}
+
+func (r gormBooks) SearchSimilar(ctx context.Context, text string, threshold float64, limit int) ([]BookMatch, error) {
+	if isSQLite(r.db) {
+		return r.searchSimilarInGo(ctx, text, threshold, limit)
+	}
+
+	// กรองด้วยตัวดำเนินการ % และ <% เพื่อให้ใช้ GIN Index (gin_trgm_ops) ได้ แทนการคำนวณทุกแถว
+	// ตัวดำเนินการอ่านเกณฑ์จาก pg_trgm.*_threshold จึงตั้งค่าเฉพาะใน Transaction นี้ก่อน ส่วน GREATEST ใช้จัดอันดับอย่างเดียว
+	var rows []bookRow
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', @t, true),
+			set_config('pg_trgm.word_similarity_threshold', @t, true)`,
+			map[string]interface{}{"t": strconv.FormatFloat(threshold, 'f', -1, 64)},
+		).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`
+			SELECT books.*, GREATEST(similarity(books.title, @q), word_similarity(@q, books.title), similarity(books.author, @q)) AS rank
+			FROM books
+			WHERE books.deleted_at IS NULL
+			  AND (books.title % @q OR @q <% books.title OR books.author % @q)
+			ORDER BY rank DESC, books.id
+			LIMIT @limit`,
+			map[string]interface{}{"q": text, "limit": limit},
+		).Scan(&rows).Error
+	})
+	return toMatches(rows), err
+}
+
+// searchLike: การค้นหาบน SQLite (ไม่มี tsvector) ทุกคำต้องพบใน Title, Author หรือ Description
+// แล้วคำนวณคะแนนด้วย textRank แบบเดียวกับ MemoryStore
+func (r gormBooks) searchLike(ctx context.Context, terms []search.Term, limit int) ([]BookMatch, error) {
+	q := r.db.WithContext(ctx).Model(&models.Book{})
+	for _, t := range terms {
+		pattern := "%" + t.Text + "%"
+		q = q.Where("(LOWER(title) LIKE ? OR LOWER(author) LIKE ? OR LOWER(description) LIKE ?)", pattern, pattern, pattern)
+	}
+	var books []models.Book
+	if err := q.Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	var matches []BookMatch
+	for _, b := range books {
+		// LOWER ของ SQLite แปลงเฉพาะ ASCII จึงตรวจซ้ำด้วย textRank
+		if rank, ok := textRank(b, terms); ok {
+			matches = append(matches, BookMatch{Book: b, Rank: rank})
+		}
+	}
+	return rankAndLimit(matches, limit), nil
+}
+
+// searchSimilarInGo: การค้นหาแบบ Trigram บน SQLite (ไม่มี pg_trgm) คำนวณความคล้ายใน Go
+// ต้องอ่านหนังสือทุกเล่ม จึงเหมาะกับฐานข้อมูลขนาดเล็กที่ใช้พัฒนาและทดสอบเท่านั้น
+func (r gormBooks) searchSimilarInGo(ctx context.Context, text string, threshold float64, limit int) ([]BookMatch, error) {
+	var books []models.Book
+	if err := r.db.WithContext(ctx).Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	var matches []BookMatch
+	for _, b := range books {
+		if rank := similarityRank(b, text); rank >= threshold {
+			matches = append(matches, BookMatch{Book: b, Rank: rank})
+		}
+	}
+	return rankAndLimit(matches, limit), nil
+}
+
+func (r gormBooks) Get(ctx context.Context, id uint) (*models.Book, error) {
+	var book models.Book
+	if err := r.db.WithContext(ctx).First(&book, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &book, nil
+}
+
+func (r gormBooks) LockForUpdate(ctx context.Context, ids []uint) ([]models.Book, error) {
+	var books []models.Book
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").Find(&books).Error
+	return books, err
+}
+
+func (r gormBooks) Create(ctx context.Context, book *models.Book) error {
+	return translateError(r.db.WithContext(ctx).Create(book).Error)
+}
+
+func (r gormBooks) Update(ctx context.Context, book *models.Book) error {
+	// ใช้ Select เพื่อให้อัปเดตค่าที่เป็น 0 หรือค่าว่างได้ด้วย (สต็อกเปลี่ยนผ่าน AdjustStock เท่านั้น)
+	return r.db.WithContext(ctx).Model(book).
+		Select("Title", "Author", "Price", "ImageURL", "Description").
+		Updates(book).Error
+}
+
+func (r gormBooks) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Book{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormBooks) AdjustStock(ctx context.Context, id uint, delta int) error {
+	return r.db.WithContext(ctx).Model(&models.Book{}).Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", delta)).Error
+}
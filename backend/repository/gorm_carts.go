@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormCarts struct {
+	db *gorm.DB
+}
+
+func (r gormCarts) List(ctx context.Context, userID uint) ([]models.CartItem, error) {
+	var items []models.CartItem
+	// ใช้ Preload("Book") เพื่อดึงรายละเอียดข้อมูลหนังสือมาพร้อมกัน
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Preload("Book").Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r gormCarts) FindByBook(ctx context.Context, userID, bookID uint) (*models.CartItem, error) {
+	var item models.CartItem
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND book_id = ?", userID, bookID).First(&item).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r gormCarts) Get(ctx context.Context, userID, itemID uint) (*models.CartItem, error) {
+	var item models.CartItem
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", itemID, userID).First(&item).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r gormCarts) Add(ctx context.Context, userID, bookID uint, quantity int) (*models.CartItem, error) {
+	db := r.db.WithContext(ctx)
+
+	// 1. รายการใหม่: จำนวนที่เพิ่มต้องไม่เกินสต็อกที่ขายได้ (สต็อกลบยอดที่ถูกจองไว้)
+	var available []int
+	if err := db.Model(&models.Book{}).Where("id = ?", bookID).
+		Pluck("stock - "+reservedStockSQL, &available).Error; err != nil {
+		return nil, err
+	}
+	if len(available) == 0 {
+		return nil, ErrNotFound
+	}
+	if quantity > available[0] {
+		return nil, ErrStockExceeded
+	}
+
+	// 2. Upsert ในคำสั่งเดียว: ชน Unique Index (user_id, book_id) เมื่อมีรายการอยู่แล้ว ให้บวกจำนวนใน SQL
+	// และบวกเฉพาะเมื่อจำนวนรวมไม่เกินสต็อกที่ขายได้ ถ้าเกินจะไม่มีแถวถูกเขียน (RowsAffected = 0)
+	item := &models.CartItem{UserID: userID, BookID: bookID, Quantity: quantity}
+	result := db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "book_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"quantity":   gorm.Expr("cart_items.quantity + excluded.quantity"),
+				"updated_at": gorm.Expr("excluded.updated_at"),
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				gorm.Expr("cart_items.quantity + excluded.quantity <= (SELECT stock - " + reservedStockSQL + " FROM books WHERE books.id = excluded.book_id)"),
+			}},
+		},
+		clause.Returning{},
+	).Omit("Book").Create(item)
+	if result.Error != nil {
+		return nil, translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStockExceeded
+	}
+	return item, nil
+}
+
+func (r gormCarts) Save(ctx context.Context, item *models.CartItem) error {
+	return translateError(r.db.WithContext(ctx).Omit("Book").Save(item).Error)
+}
+
+func (r gormCarts) Delete(ctx context.Context, userID, itemID uint) error {
+	// ลบโดยตรวจสอบว่าเป็นของเจ้าของ User จริงๆ เพื่อความปลอดภัย
+	// ลบจริงด้วย Unscoped: แถวที่ Soft Delete ไว้จะชน Unique Index (user_id, book_id) ตอนเพิ่มเล่มเดิมอีกครั้ง
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ? AND user_id = ?", itemID, userID).Delete(&models.CartItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormCarts) Clear(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&models.CartItem{}).Error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"my-fiber-app/database"
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB: เปิดฐานข้อมูลตาม driver แล้วรัน Migration
+// SQLite ใช้ ":memory:" ส่วน PostgreSQL ใช้ DB_HOST, DB_USER, DB_PASSWORD, DB_NAME, DB_PORT
+// และรันเฉพาะเมื่อตั้ง TEST_POSTGRES=1 (ฐานข้อมูลนั้นจะถูก Migrate ขึ้นเป็นเวอร์ชันล่าสุด)
+func openTestDB(t *testing.T, driver string) *gorm.DB {
+	t.Helper()
+	t.Setenv("DB_DRIVER", driver)
+	if driver == database.DriverSQLite {
+		t.Setenv("DB_NAME", ":memory:")
+	} else if os.Getenv("TEST_POSTGRES") != "1" {
+		t.Skip("set TEST_POSTGRES=1 and DB_HOST, DB_USER, DB_PASSWORD, DB_NAME, DB_PORT to run against PostgreSQL")
+	}
+
+	db, err := database.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+// TestGormCartsAddConcurrent: หลาย Goroutine เพิ่มหนังสือเล่มเดียวกันลงตะกร้าของผู้ใช้คนเดียวกันพร้อมกัน
+// ต้องได้รายการเดียว จำนวนรวมเท่ากับยอดของครั้งที่สำเร็จ และไม่เคยเกินสต็อกระหว่างทาง
+func TestGormCartsAddConcurrent(t *testing.T) {
+	for _, driver := range []string{database.DriverSQLite, database.DriverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			db := openTestDB(t, driver)
+			testCartsAddConcurrent(t, db)
+		})
+	}
+}
+
+func testCartsAddConcurrent(t *testing.T, db *gorm.DB) {
+	const (
+		stock    = 10
+		workers  = 20
+		quantity = 3
+	)
+	ctx := context.Background()
+
+	// 1. ผู้ใช้และหนังสือของการทดสอบนี้ (ลบทิ้งตอนจบ เผื่อรันบนฐานข้อมูลที่ใช้ร่วมกัน)
+	user := &models.User{Email: fmt.Sprintf("cart-race-%d@example.com", time.Now().UnixNano()), Password: "x", Role: models.RoleUser}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	book := &models.Book{Title: "Concurrent Carts", Price: 100, Stock: stock}
+	if err := db.Create(book).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.CartItem{})
+		db.Unscoped().Delete(book)
+		db.Unscoped().Delete(user)
+	})
+
+	// 2. ตรวจจำนวนในตะกร้าเป็นระยะระหว่างที่ทุก Goroutine กำลังเพิ่ม
+	carts := NewGormStore(db).Carts()
+	done := make(chan struct{})
+	sampled := make(chan int, 1)
+	go func() {
+		highest := 0
+		for {
+			select {
+			case <-done:
+				sampled <- highest
+				return
+			default:
+			}
+			var total int
+			db.Model(&models.CartItem{}).Where("user_id = ? AND book_id = ?", user.ID, book.ID).
+				Select("COALESCE(SUM(quantity), 0)").Scan(&total)
+			highest = max(highest, total)
+		}
+	}()
+
+	// 3. เพิ่มพร้อมกัน: สำเร็จหรือได้ ErrStockExceeded เท่านั้น
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		errs      []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := carts.Add(ctx, user.ID, book.ID, quantity)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case !errors.Is(err, ErrStockExceeded):
+				errs = append(errs, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(done)
+	highest := <-sampled
+
+	for _, err := range errs {
+		t.Errorf("Add: unexpected error %v", err)
+	}
+	if want := stock / quantity; succeeded != want {
+		t.Errorf("successful adds = %d, want %d", succeeded, want)
+	}
+
+	// 4. รายการเดียว จำนวนรวมถูกต้อง และไม่เคยเกินสต็อก
+	var items []models.CartItem
+	if err := db.Where("user_id = ? AND book_id = ?", user.ID, book.ID).Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("cart rows = %d, want 1", len(items))
+	}
+	if want := succeeded * quantity; items[0].Quantity != want {
+		t.Errorf("quantity = %d, want %d (%d successful adds of %d)", items[0].Quantity, want, succeeded, quantity)
+	}
+	if items[0].Quantity > stock || highest > stock {
+		t.Errorf("quantity went over stock %d (final %d, highest seen %d)", stock, items[0].Quantity, highest)
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormGuestCarts struct {
+	db *gorm.DB
+}
+
+func (r gormGuestCarts) Touch(ctx context.Context, cartID string, at time.Time) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at"}),
+	}).Create(&models.GuestCart{ID: cartID, CreatedAt: at, UpdatedAt: at}).Error
+}
+
+func (r gormGuestCarts) List(ctx context.Context, cartID string) ([]models.GuestCartItem, error) {
+	var items []models.GuestCartItem
+	err := r.db.WithContext(ctx).Where("guest_cart_id = ?", cartID).Preload("Book").Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r gormGuestCarts) FindByBook(ctx context.Context, cartID string, bookID uint) (*models.GuestCartItem, error) {
+	var item models.GuestCartItem
+	if err := r.db.WithContext(ctx).Where("guest_cart_id = ? AND book_id = ?", cartID, bookID).First(&item).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r gormGuestCarts) Get(ctx context.Context, cartID string, itemID uint) (*models.GuestCartItem, error) {
+	var item models.GuestCartItem
+	if err := r.db.WithContext(ctx).Where("id = ? AND guest_cart_id = ?", itemID, cartID).First(&item).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r gormGuestCarts) CreateItem(ctx context.Context, item *models.GuestCartItem) error {
+	return translateError(r.db.WithContext(ctx).Omit("Book").Create(item).Error)
+}
+
+func (r gormGuestCarts) SaveItem(ctx context.Context, item *models.GuestCartItem) error {
+	return r.db.WithContext(ctx).Omit("Book").Save(item).Error
+}
+
+func (r gormGuestCarts) DeleteItem(ctx context.Context, cartID string, itemID uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND guest_cart_id = ?", itemID, cartID).Delete(&models.GuestCartItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormGuestCarts) Delete(ctx context.Context, cartID string) error {
+	// รายการถูกลบตามด้วย ON DELETE CASCADE
+	return r.db.WithContext(ctx).Where("id = ?", cartID).Delete(&models.GuestCart{}).Error
+}
+
+func (r gormGuestCarts) PurgeStale(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where(timeExpr(r.db, "updated_at")+" < "+timeExpr(r.db, "?"), before).
+		Delete(&models.GuestCart{}).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+)
+
+type gormStockMovements struct {
+	db *gorm.DB
+}
+
+func (r gormStockMovements) Create(ctx context.Context, m *models.StockMovement) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r gormStockMovements) List(ctx context.Context, f StockMovementFilter, limit, offset int) ([]models.StockMovement, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.StockMovement{})
+	if f.BookID != 0 {
+		q = q.Where("book_id = ?", f.BookID)
+	}
+	if f.Type != "" {
+		q = q.Where("type = ?", f.Type)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var movements []models.StockMovement
+	err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&movements).Error
+	return movements, total, err
+}
+
+func (r gormStockMovements) Total(ctx context.Context, bookID uint) (int, error) {
+	var total int
+	err := r.db.WithContext(ctx).Model(&models.StockMovement{}).Where("book_id = ?", bookID).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&total).Error
+	return total, err
+}
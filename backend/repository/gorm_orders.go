@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormOrders struct {
+	db *gorm.DB
+}
+
+func (r gormOrders) Create(ctx context.Context, order *models.Order) error {
+	return r.db.WithContext(ctx).Create(order).Error
+}
+
+func (r gormOrders) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Order{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []models.Order
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Preload("Items").
+		Order(timeExpr(r.db, "created_at") + " DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+func (r gormOrders) GetForUser(ctx context.Context, userID, id uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).
+		Preload("Items").
+		First(&order).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r gormOrders) Get(ctx context.Context, id uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.WithContext(ctx).Preload("Items").
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&order, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r gormOrders) LockForUpdate(ctx context.Context, id uint) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r gormOrders) Items(ctx context.Context, orderID uint) ([]models.OrderItem, error) {
+	var items []models.OrderItem
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r gormOrders) UpdateStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r gormOrders) AddTransition(ctx context.Context, t *models.OrderTransition) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
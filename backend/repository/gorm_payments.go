@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPayments struct {
+	db *gorm.DB
+}
+
+func (r gormPayments) Create(ctx context.Context, p *models.Payment) error {
+	return translateError(r.db.WithContext(ctx).Create(p).Error)
+}
+
+func (r gormPayments) GetByIntentForUpdate(ctx context.Context, intentID string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("intent_id = ?", intentID).First(&payment).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+func (r gormPayments) IntentExists(ctx context.Context, intentID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Payment{}).Where("intent_id = ?", intentID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormPayments) FindByOrderStatus(ctx context.Context, orderID uint, status string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.WithContext(ctx).Where("order_id = ? AND status = ?", orderID, status).First(&payment).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+func (r gormPayments) UpdateStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&models.Payment{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r gormPayments) RecordEvent(ctx context.Context, e *models.PaymentEvent) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(e)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
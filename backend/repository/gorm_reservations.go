@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+)
+
+// reservedStockSQL: ยอดจองรวมของหนังสือในแถว books ปัจจุบัน (ใช้ในเงื่อนไขที่เทียบกับสต็อกที่ขายได้)
+const reservedStockSQL = "COALESCE((SELECT SUM(stock_reservations.quantity) FROM stock_reservations WHERE stock_reservations.book_id = books.id), 0)"
+
+type gormReservations struct {
+	db *gorm.DB
+}
+
+func (r gormReservations) Create(ctx context.Context, res *models.StockReservation) error {
+	return r.db.WithContext(ctx).Create(res).Error
+}
+
+func (r gormReservations) Reserved(ctx context.Context, bookIDs []uint) (map[uint]int, error) {
+	reserved := map[uint]int{}
+	if len(bookIDs) == 0 {
+		return reserved, nil
+	}
+	var rows []struct {
+		BookID   uint
+		Quantity int
+	}
+	err := r.db.WithContext(ctx).Model(&models.StockReservation{}).
+		Select("book_id, SUM(quantity) AS quantity").
+		Where("book_id IN ?", bookIDs).Group("book_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		reserved[row.BookID] = row.Quantity
+	}
+	return reserved, nil
+}
+
+func (r gormReservations) ListByOrder(ctx context.Context, orderID uint) ([]models.StockReservation, error) {
+	var items []models.StockReservation
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r gormReservations) DeleteByOrder(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.StockReservation{}).Error
+}
+
+func (r gormReservations) ExpiredOrders(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.StockReservation{}).
+		Where(timeExpr(r.db, "expires_at")+" < "+timeExpr(r.db, "?"), now).
+		Group("order_id").Order("MIN("+timeExpr(r.db, "expires_at")+"), order_id").
+		Limit(limit).Pluck("order_id", &ids).Error
+	return ids, err
+}
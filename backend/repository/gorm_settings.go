@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormSettings struct {
+	db *gorm.DB
+}
+
+func (r gormSettings) List(ctx context.Context) ([]models.Setting, error) {
+	var settings []models.Setting
+	err := r.db.WithContext(ctx).Order("key").Find(&settings).Error
+	return settings, err
+}
+
+func (r gormSettings) Get(ctx context.Context, key string) (string, error) {
+	var setting models.Setting
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&setting).Error; err != nil {
+		return "", translateError(err)
+	}
+	return setting.Value, nil
+}
+
+func (r gormSettings) Set(ctx context.Context, key, value string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&models.Setting{Key: key, Value: value, UpdatedAt: time.Now()}).Error
+}
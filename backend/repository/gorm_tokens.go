@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormTokens struct {
+	db *gorm.DB
+}
+
+func (r gormTokens) Create(ctx context.Context, t *models.RefreshToken) error {
+	return translateError(r.db.WithContext(ctx).Create(t).Error)
+}
+
+func (r gormTokens) GetByHashForUpdate(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+func (r gormTokens) MarkUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).Where("id = ?", id).Update("used_at", at).Error
+}
+
+func (r gormTokens) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).Update("revoked_at", at).Error
+}
+
+func (r gormTokens) RevokeUser(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", at).Error
+}
+
+type gormRevocations struct {
+	db *gorm.DB
+}
+
+func (r gormRevocations) RevokeToken(ctx context.Context, t *models.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(t).Error
+}
+
+func (r gormRevocations) RevokeUser(ctx context.Context, userID uint, before time.Time) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before"}),
+	}).Create(&models.SessionRevocation{UserID: userID, RevokedBefore: before}).Error
+}
+
+func (r gormRevocations) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.SessionRevocation{}).
+		Where("user_id = ? AND "+timeExpr(r.db, "revoked_before")+" > "+timeExpr(r.db, "?"), userID, issuedAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormRevocations) PurgeExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where(timeExpr(r.db, "expires_at")+" < "+timeExpr(r.db, "?"), now).Delete(&models.RevokedToken{}).Error
+}
+
+type gormPasswordResets struct {
+	db *gorm.DB
+}
+
+func (r gormPasswordResets) Create(ctx context.Context, t *models.PasswordResetToken) error {
+	return translateError(r.db.WithContext(ctx).Create(t).Error)
+}
+
+func (r gormPasswordResets) GetByHashForUpdate(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+func (r gormPasswordResets) InvalidateUser(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Update("used_at", at).Error
+}
+
+func (r gormPasswordResets) MarkUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).Where("id = ?", id).Update("used_at", at).Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"my-fiber-app/models"
+
+	"gorm.io/gorm"
+)
+
+type gormUsers struct {
+	db *gorm.DB
+}
+
+func (r gormUsers) Create(ctx context.Context, user *models.User) error {
+	return translateError(r.db.WithContext(ctx).Create(user).Error)
+}
+
+func (r gormUsers) Get(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r gormUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r gormUsers) List(ctx context.Context, f UserFilter, limit, offset int) ([]models.User, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.User{})
+	if f.Query != "" {
+		pattern := "%" + strings.ToLower(f.Query) + "%"
+		q = q.Where("(LOWER(email) LIKE ? OR LOWER(name) LIKE ?)", pattern, pattern)
+	}
+	if f.Role != "" {
+		q = q.Where("role = ?", f.Role)
+	}
+	if f.Suspended != nil {
+		if *f.Suspended {
+			q = q.Where("suspended_at IS NOT NULL")
+		} else {
+			q = q.Where("suspended_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []models.User
+	err := q.Order(timeExpr(r.db, "created_at") + " DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&users).Error
+	return users, total, err
+}
+
+func (r gormUsers) UpdatePassword(ctx context.Context, id uint, hash string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("password", hash).Error
+}
+
+func (r gormUsers) MarkEmailVerified(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).Update("email_verified_at", at).Error
+}
+
+func (r gormUsers) SetLanguage(ctx context.Context, id uint, language string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("language", language).Error
+}
+
+func (r gormUsers) UpdateRole(ctx context.Context, id uint, role string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r gormUsers) Suspend(ctx context.Context, id uint, at time.Time, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"suspended_at": at, "suspend_reason": reason}).Error
+}
+
+func (r gormUsers) Unsuspend(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"suspended_at": nil, "suspend_reason": ""}).Error
+}
+
+func (r gormUsers) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &role, nil
+}
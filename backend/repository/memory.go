@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+// memData: ข้อมูลทั้งหมดของ MemoryStore (เก็บเป็นค่า ไม่ใช่ Pointer เพื่อให้ Clone ได้ง่าย)
+type memData struct {
+	seq         map[string]uint // ID ล่าสุดของแต่ละตาราง
+	books       map[uint]models.Book
+	users       map[uint]models.User
+	roles       map[string]models.Role
+	cartItems   map[uint]models.CartItem
+	guestCarts  map[string]models.GuestCart // ไม่เก็บ Items ไว้ในนี้ ดู guestItems
+	guestItems  map[uint]models.GuestCartItem
+	orders      map[uint]models.Order // ไม่เก็บ Items/Transitions ไว้ในนี้ ดูแผนที่ของตัวเอง
+	orderItems  map[uint]models.OrderItem
+	transitions map[uint]models.OrderTransition
+	reserved    map[uint]models.StockReservation
+	movements   map[uint]models.StockMovement
+	payments    map[uint]models.Payment
+	events      map[string]models.PaymentEvent // Key คือ EventID
+	tokens      map[uint]models.RefreshToken
+	revoked     map[string]models.RevokedToken // Key คือ JTI
+	sessions    map[uint]time.Time             // ผู้ใช้ -> เพิกถอน Token ที่ออกก่อนเวลานี้
+	resets      map[uint]models.PasswordResetToken
+	settings    map[string]models.Setting
+	auditLogs   map[uint]models.AuditLog
+}
+
+// nextID: ออก ID ใหม่ของตารางที่ระบุ
+func (d *memData) nextID(table string) uint {
+	d.seq[table]++
+	return d.seq[table]
+}
+
+// clone: สำเนาข้อมูลทั้งหมด ใช้เป็นจุดย้อนกลับเมื่อ Transaction ล้มเหลว
+func (d *memData) clone() *memData {
+	return &memData{
+		seq:         maps.Clone(d.seq),
+		books:       maps.Clone(d.books),
+		users:       maps.Clone(d.users),
+		roles:       maps.Clone(d.roles),
+		cartItems:   maps.Clone(d.cartItems),
+		guestCarts:  maps.Clone(d.guestCarts),
+		guestItems:  maps.Clone(d.guestItems),
+		orders:      maps.Clone(d.orders),
+		orderItems:  maps.Clone(d.orderItems),
+		transitions: maps.Clone(d.transitions),
+		reserved:    maps.Clone(d.reserved),
+		movements:   maps.Clone(d.movements),
+		payments:    maps.Clone(d.payments),
+		events:      maps.Clone(d.events),
+		tokens:      maps.Clone(d.tokens),
+		revoked:     maps.Clone(d.revoked),
+		sessions:    maps.Clone(d.sessions),
+		resets:      maps.Clone(d.resets),
+		settings:    maps.Clone(d.settings),
+		auditLogs:   maps.Clone(d.auditLogs),
+	}
+}
+
+// memState: สถานะที่ Store และทุก Transaction ใช้ร่วมกัน
+type memState struct {
+	mu   sync.Mutex
+	data *memData
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore: Store ที่เก็บข้อมูลในหน่วยความจำ สำหรับทดสอบ Handler โดยไม่ต้องมีฐานข้อมูล
+// Transaction ใช้ Mutex ตัวเดียวทั้ง Store (เทียบเท่าการล็อกทุกแถว) และย้อนข้อมูลกลับเมื่อเกิด Error
+type MemoryStore struct {
+	state *memState
+	inTx  bool // true เมื่ออยู่ใน Transaction (ถือ Mutex อยู่แล้ว)
+}
+
+// NewMemoryStore: สร้าง Store ว่าง พร้อมบทบาทและสิทธิ์เริ่มต้นเหมือนฐานข้อมูลจริง
+func NewMemoryStore() *MemoryStore {
+	d := &memData{
+		seq:         map[string]uint{},
+		books:       map[uint]models.Book{},
+		users:       map[uint]models.User{},
+		roles:       map[string]models.Role{},
+		cartItems:   map[uint]models.CartItem{},
+		guestCarts:  map[string]models.GuestCart{},
+		guestItems:  map[uint]models.GuestCartItem{},
+		orders:      map[uint]models.Order{},
+		orderItems:  map[uint]models.OrderItem{},
+		transitions: map[uint]models.OrderTransition{},
+		reserved:    map[uint]models.StockReservation{},
+		movements:   map[uint]models.StockMovement{},
+		payments:    map[uint]models.Payment{},
+		events:      map[string]models.PaymentEvent{},
+		tokens:      map[uint]models.RefreshToken{},
+		revoked:     map[string]models.RevokedToken{},
+		sessions:    map[uint]time.Time{},
+		resets:      map[uint]models.PasswordResetToken{},
+		settings:    map[string]models.Setting{},
+		auditLogs:   map[uint]models.AuditLog{},
+	}
+
+	perms := map[string]models.Permission{}
+	for name, desc := range models.DefaultPermissions {
+		p := models.Permission{Name: name, Description: desc}
+		p.ID = d.nextID("permissions")
+		perms[name] = p
+	}
+	for name, permNames := range models.DefaultRoles {
+		role := models.Role{Name: name}
+		role.ID = d.nextID("roles")
+		for _, pn := range permNames {
+			role.Permissions = append(role.Permissions, perms[pn])
+		}
+		d.roles[name] = role
+	}
+
+	return &MemoryStore{state: &memState{data: d}}
+}
+
+// do: เรียก fn พร้อมข้อมูล (ล็อก Mutex ให้ถ้ายังไม่อยู่ใน Transaction)
+func (s *MemoryStore) do(fn func(d *memData) error) error {
+	if !s.inTx {
+		s.state.mu.Lock()
+		defer s.state.mu.Unlock()
+	}
+	return fn(s.state.data)
+}
+
+func (s *MemoryStore) Books() BookRepository                   { return memBooks{s: s} }
+func (s *MemoryStore) Users() UserRepository                   { return memUsers{s: s} }
+func (s *MemoryStore) Carts() CartRepository                   { return memCarts{s: s} }
+func (s *MemoryStore) GuestCarts() GuestCartRepository         { return memGuestCarts{s: s} }
+func (s *MemoryStore) Orders() OrderRepository                 { return memOrders{s: s} }
+func (s *MemoryStore) Reservations() ReservationRepository     { return memReservations{s: s} }
+func (s *MemoryStore) StockMovements() StockMovementRepository { return memStockMovements{s: s} }
+func (s *MemoryStore) Payments() PaymentRepository             { return memPayments{s: s} }
+func (s *MemoryStore) Tokens() TokenRepository                 { return memTokens{s: s} }
+func (s *MemoryStore) Revocations() RevocationRepository       { return memRevocations{s: s} }
+func (s *MemoryStore) PasswordResets() PasswordResetRepository { return memPasswordResets{s: s} }
+func (s *MemoryStore) Settings() SettingRepository             { return memSettings{s: s} }
+func (s *MemoryStore) AuditLogs() AuditLogRepository           { return memAuditLogs{s: s} }
+
+func (s *MemoryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if s.inTx {
+		// Transaction ซ้อน: ใช้ Transaction เดิม
+		return fn(s)
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	snapshot := s.state.data.clone()
+	if err := fn(&MemoryStore{state: s.state, inTx: true}); err != nil {
+		s.state.data = snapshot
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memAuditLogs struct {
+	s *MemoryStore
+}
+
+func (r memAuditLogs) Create(_ context.Context, entry *models.AuditLog) error {
+	return r.s.do(func(d *memData) error {
+		entry.ID = d.nextID("audit_logs")
+		entry.CreatedAt = time.Now()
+		d.auditLogs[entry.ID] = *entry
+		return nil
+	})
+}
+
+func (r memAuditLogs) List(_ context.Context, f AuditLogFilter, limit, offset int) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	err := r.s.do(func(d *memData) error {
+		for _, e := range d.auditLogs {
+			if (f.ActorID != 0 && e.ActorID != f.ActorID) ||
+				(f.Action != "" && e.Action != f.Action) ||
+				(f.TargetType != "" && e.TargetType != f.TargetType) ||
+				(f.TargetID != 0 && e.TargetID != f.TargetID) {
+				continue
+			}
+			entries = append(entries, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ใหม่ไปเก่า (ID ออกตามลำดับเวลาที่บันทึก)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	total := int64(len(entries))
+	if offset >= len(entries) {
+		return nil, total, nil
+	}
+	entries = entries[offset:]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"my-fiber-app/models"
+	"my-fiber-app/search"
+)
+
+type memBooks struct {
+	s *MemoryStore
+}
+
+// matchesFilter: ตรวจสอบหนังสือกับตัวกรอง (ให้ผลเหมือน gormBooks.filtered)
+func matchesFilter(d *memData, b models.Book, f BookFilter) bool {
+	if f.Author != "" && !strings.Contains(strings.ToLower(b.Author), strings.ToLower(f.Author)) {
+		return false
+	}
+	if f.MinPrice != nil && b.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && b.Price > *f.MaxPrice {
+		return false
+	}
+	if f.InStock && b.Stock-d.reservedStock(b.ID) <= 0 {
+		return false
+	}
+	return true
+}
+
+// compareBookKey: เปรียบเทียบหนังสือกับตำแหน่ง (ค่าคอลัมน์, id) คืน -1, 0 หรือ 1
+func compareBookKey(b models.Book, column string, value interface{}, id uint) int {
+	c := 0
+	switch column {
+	case "price":
+		v, _ := value.(int)
+		c = compareOrdered(b.Price, v)
+	case "title":
+		v, _ := value.(string)
+		c = compareOrdered(b.Title, v)
+	case "created_at":
+		v, _ := value.(time.Time)
+		c = b.CreatedAt.Compare(v)
+	}
+	if c == 0 {
+		c = compareOrdered(b.ID, id)
+	}
+	return c
+}
+
+func compareOrdered[T int | uint | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// sortValue: ค่าของคอลัมน์ที่ใช้เรียงของหนังสือเล่มนี้
+func sortValue(b models.Book, column string) interface{} {
+	switch column {
+	case "price":
+		return b.Price
+	case "created_at":
+		return b.CreatedAt
+	default:
+		return b.Title
+	}
+}
+
+func (r memBooks) List(_ context.Context, q BookQuery) ([]models.Book, error) {
+	if !bookSortColumns[q.SortBy] {
+		return nil, fmt.Errorf("repository: unsupported sort column %q", q.SortBy)
+	}
+
+	var books []models.Book
+	err := r.s.do(func(d *memData) error {
+		for _, b := range d.books {
+			if !matchesFilter(d, b, q.BookFilter) {
+				continue
+			}
+			if q.After != nil {
+				c := compareBookKey(b, q.SortBy, q.After.Value, q.After.ID)
+				if (!q.Desc && c <= 0) || (q.Desc && c >= 0) {
+					continue
+				}
+			}
+			books = append(books, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(books, func(i, j int) bool {
+		c := compareBookKey(books[i], q.SortBy, sortValue(books[j], q.SortBy), books[j].ID)
+		if q.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	if q.Offset >= len(books) {
+		return nil, nil
+	}
+	books = books[q.Offset:]
+	if q.Limit > 0 && len(books) > q.Limit {
+		books = books[:q.Limit]
+	}
+	return books, nil
+}
+
+func (r memBooks) Count(_ context.Context, f BookFilter) (int64, error) {
+	var total int64
+	err := r.s.do(func(d *memData) error {
+		for _, b := range d.books {
+			if matchesFilter(d, b, f) {
+				total++
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+// Search: ทุกคำต้องพบในชื่อ ผู้แต่ง หรือรายละเอียด คะแนนถ่วงน้ำหนักแบบเดียวกับ search_vector (A > B > C)
+func (r memBooks) Search(_ context.Context, terms []search.Term, limit int) ([]BookMatch, error) {
+	var matches []BookMatch
+	err := r.s.do(func(d *memData) error {
+		for _, b := range d.books {
+			if rank, ok := textRank(b, terms); ok {
+				matches = append(matches, BookMatch{Book: b, Rank: rank})
+			}
+		}
+		return nil
+	})
+	return rankAndLimit(matches, limit), err
+}
+
+func (r memBooks) SearchSimilar(_ context.Context, text string, threshold float64, limit int) ([]BookMatch, error) {
+	var matches []BookMatch
+	err := r.s.do(func(d *memData) error {
+		for _, b := range d.books {
+			if rank := similarityRank(b, text); rank >= threshold {
+				matches = append(matches, BookMatch{Book: b, Rank: rank})
+			}
+		}
+		return nil
+	})
+	return rankAndLimit(matches, limit), err
+}
+
+func (r memBooks) Get(_ context.Context, id uint) (*models.Book, error) {
+	var book *models.Book
+	err := r.s.do(func(d *memData) error {
+		b, ok := d.books[id]
+		if !ok {
+			return ErrNotFound
+		}
+		book = &b
+		return nil
+	})
+	return book, err
+}
+
+func (r memBooks) LockForUpdate(_ context.Context, ids []uint) ([]models.Book, error) {
+	var books []models.Book
+	err := r.s.do(func(d *memData) error {
+		for _, id := range ids {
+			if b, ok := d.books[id]; ok {
+				books = append(books, b)
+			}
+		}
+		return nil
+	})
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books, err
+}
+
+func (r memBooks) Create(_ context.Context, book *models.Book) error {
+	return r.s.do(func(d *memData) error {
+		now := time.Now()
+		book.ID = d.nextID("books")
+		book.CreatedAt, book.UpdatedAt = now, now
+		d.books[book.ID] = *book
+		return nil
+	})
+}
+
+func (r memBooks) Update(_ context.Context, book *models.Book) error {
+	return r.s.do(func(d *memData) error {
+		stored, ok := d.books[book.ID]
+		if !ok {
+			return ErrNotFound
+		}
+		stored.Title, stored.Author, stored.Price = book.Title, book.Author, book.Price
+		stored.ImageURL, stored.Description = book.ImageURL, book.Description
+		stored.UpdatedAt = time.Now()
+		d.books[book.ID] = stored
+		*book = stored
+		return nil
+	})
+}
+
+func (r memBooks) Delete(_ context.Context, id uint) error {
+	return r.s.do(func(d *memData) error {
+		if _, ok := d.books[id]; !ok {
+			return ErrNotFound
+		}
+		delete(d.books, id)
+		return nil
+	})
+}
+
+func (r memBooks) AdjustStock(_ context.Context, id uint, delta int) error {
+	return r.s.do(func(d *memData) error {
+		b, ok := d.books[id]
+		if !ok {
+			return nil // เหมือน UPDATE ที่ไม่มีแถวตรงเงื่อนไข
+		}
+		b.Stock += delta
+		b.UpdatedAt = time.Now()
+		d.books[id] = b
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memCarts struct {
+	s *MemoryStore
+}
+
+func (r memCarts) List(_ context.Context, userID uint) ([]models.CartItem, error) {
+	var items []models.CartItem
+	err := r.s.do(func(d *memData) error {
+		for _, item := range d.cartItems {
+			if item.UserID != userID {
+				continue
+			}
+			item.Book = d.books[item.BookID]
+			items = append(items, item)
+		}
+		return nil
+	})
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, err
+}
+
+func (r memCarts) FindByBook(_ context.Context, userID, bookID uint) (*models.CartItem, error) {
+	var found *models.CartItem
+	err := r.s.do(func(d *memData) error {
+		for _, item := range d.cartItems {
+			if item.UserID == userID && item.BookID == bookID {
+				found = &item
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return found, err
+}
+
+func (r memCarts) Get(_ context.Context, userID, itemID uint) (*models.CartItem, error) {
+	var found *models.CartItem
+	err := r.s.do(func(d *memData) error {
+		item, ok := d.cartItems[itemID]
+		if !ok || item.UserID != userID {
+			return ErrNotFound
+		}
+		found = &item
+		return nil
+	})
+	return found, err
+}
+
+func (r memCarts) Add(_ context.Context, userID, bookID uint, quantity int) (*models.CartItem, error) {
+	var added models.CartItem
+	err := r.s.do(func(d *memData) error {
+		book, ok := d.books[bookID]
+		if !ok {
+			return ErrNotFound
+		}
+		// หนึ่งรายการต่อหนังสือหนึ่งเล่มของผู้ใช้ (เหมือน Unique Index (user_id, book_id))
+		now := time.Now()
+		item := models.CartItem{UserID: userID, BookID: bookID}
+		item.CreatedAt = now
+		for _, existing := range d.cartItems {
+			if existing.UserID == userID && existing.BookID == bookID {
+				item = existing
+				break
+			}
+		}
+		if item.Quantity+quantity > book.Stock-d.reservedStock(bookID) {
+			return ErrStockExceeded
+		}
+		if item.ID == 0 {
+			item.ID = d.nextID("cart_items")
+		}
+		item.Quantity += quantity
+		item.UpdatedAt = now
+		d.cartItems[item.ID] = item
+		added = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (r memCarts) Save(_ context.Context, item *models.CartItem) error {
+	return r.s.do(func(d *memData) error {
+		if item.ID == 0 {
+			item.ID = d.nextID("cart_items")
+			item.CreatedAt = time.Now()
+		}
+		item.UpdatedAt = time.Now()
+		stored := *item
+		stored.Book = models.Book{}
+		d.cartItems[item.ID] = stored
+		return nil
+	})
+}
+
+func (r memCarts) Delete(_ context.Context, userID, itemID uint) error {
+	return r.s.do(func(d *memData) error {
+		item, ok := d.cartItems[itemID]
+		if !ok || item.UserID != userID {
+			return ErrNotFound
+		}
+		delete(d.cartItems, itemID)
+		return nil
+	})
+}
+
+func (r memCarts) Clear(_ context.Context, userID uint) error {
+	return r.s.do(func(d *memData) error {
+		for id, item := range d.cartItems {
+			if item.UserID == userID {
+				delete(d.cartItems, id)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memGuestCarts struct {
+	s *MemoryStore
+}
+
+func (r memGuestCarts) Touch(_ context.Context, cartID string, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		cart, ok := d.guestCarts[cartID]
+		if !ok {
+			cart = models.GuestCart{ID: cartID, CreatedAt: at}
+		}
+		cart.UpdatedAt = at
+		d.guestCarts[cartID] = cart
+		return nil
+	})
+}
+
+func (r memGuestCarts) List(_ context.Context, cartID string) ([]models.GuestCartItem, error) {
+	var items []models.GuestCartItem
+	err := r.s.do(func(d *memData) error {
+		for _, item := range d.guestItems {
+			if item.GuestCartID != cartID {
+				continue
+			}
+			item.Book = d.books[item.BookID]
+			items = append(items, item)
+		}
+		return nil
+	})
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, err
+}
+
+func (r memGuestCarts) FindByBook(_ context.Context, cartID string, bookID uint) (*models.GuestCartItem, error) {
+	var found *models.GuestCartItem
+	err := r.s.do(func(d *memData) error {
+		for _, item := range d.guestItems {
+			if item.GuestCartID == cartID && item.BookID == bookID {
+				found = &item
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return found, err
+}
+
+func (r memGuestCarts) Get(_ context.Context, cartID string, itemID uint) (*models.GuestCartItem, error) {
+	var found *models.GuestCartItem
+	err := r.s.do(func(d *memData) error {
+		item, ok := d.guestItems[itemID]
+		if !ok || item.GuestCartID != cartID {
+			return ErrNotFound
+		}
+		found = &item
+		return nil
+	})
+	return found, err
+}
+
+func (r memGuestCarts) CreateItem(_ context.Context, item *models.GuestCartItem) error {
+	return r.s.do(func(d *memData) error {
+		if _, ok := d.guestCarts[item.GuestCartID]; !ok {
+			return ErrNotFound // เหมือน Foreign Key ไปยัง guest_carts
+		}
+		for _, existing := range d.guestItems {
+			if existing.GuestCartID == item.GuestCartID && existing.BookID == item.BookID {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		item.ID = d.nextID("guest_cart_items")
+		item.CreatedAt, item.UpdatedAt = now, now
+		stored := *item
+		stored.Book = models.Book{}
+		d.guestItems[item.ID] = stored
+		return nil
+	})
+}
+
+func (r memGuestCarts) SaveItem(_ context.Context, item *models.GuestCartItem) error {
+	return r.s.do(func(d *memData) error {
+		if item.ID == 0 {
+			item.ID = d.nextID("guest_cart_items")
+			item.CreatedAt = time.Now()
+		}
+		item.UpdatedAt = time.Now()
+		stored := *item
+		stored.Book = models.Book{}
+		d.guestItems[item.ID] = stored
+		return nil
+	})
+}
+
+func (r memGuestCarts) DeleteItem(_ context.Context, cartID string, itemID uint) error {
+	return r.s.do(func(d *memData) error {
+		item, ok := d.guestItems[itemID]
+		if !ok || item.GuestCartID != cartID {
+			return ErrNotFound
+		}
+		delete(d.guestItems, itemID)
+		return nil
+	})
+}
+
+func (r memGuestCarts) Delete(_ context.Context, cartID string) error {
+	return r.s.do(func(d *memData) error {
+		deleteGuestCart(d, cartID)
+		return nil
+	})
+}
+
+func (r memGuestCarts) PurgeStale(_ context.Context, before time.Time) error {
+	return r.s.do(func(d *memData) error {
+		for id, cart := range d.guestCarts {
+			if cart.UpdatedAt.Before(before) {
+				deleteGuestCart(d, id)
+			}
+		}
+		return nil
+	})
+}
+
+// deleteGuestCart: ลบตะกร้าพร้อมรายการ (เหมือน ON DELETE CASCADE)
+func deleteGuestCart(d *memData, cartID string) {
+	delete(d.guestCarts, cartID)
+	for id, item := range d.guestItems {
+		if item.GuestCartID == cartID {
+			delete(d.guestItems, id)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memStockMovements struct {
+	s *MemoryStore
+}
+
+func (r memStockMovements) Create(_ context.Context, m *models.StockMovement) error {
+	return r.s.do(func(d *memData) error {
+		m.ID = d.nextID("stock_movements")
+		m.CreatedAt = time.Now()
+		d.movements[m.ID] = *m
+		return nil
+	})
+}
+
+func (r memStockMovements) List(_ context.Context, f StockMovementFilter, limit, offset int) ([]models.StockMovement, int64, error) {
+	var movements []models.StockMovement
+	err := r.s.do(func(d *memData) error {
+		for _, m := range d.movements {
+			if (f.BookID != 0 && m.BookID != f.BookID) || (f.Type != "" && m.Type != f.Type) {
+				continue
+			}
+			movements = append(movements, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ใหม่ไปเก่า (ID ออกตามลำดับเวลาที่บันทึก)
+	sort.Slice(movements, func(i, j int) bool { return movements[i].ID > movements[j].ID })
+	total := int64(len(movements))
+	if offset >= len(movements) {
+		return nil, total, nil
+	}
+	movements = movements[offset:]
+	if len(movements) > limit {
+		movements = movements[:limit]
+	}
+	return movements, total, nil
+}
+
+func (r memStockMovements) Total(_ context.Context, bookID uint) (int, error) {
+	total := 0
+	err := r.s.do(func(d *memData) error {
+		for _, m := range d.movements {
+			if m.BookID == bookID {
+				total += m.Quantity
+			}
+		}
+		return nil
+	})
+	return total, err
+}
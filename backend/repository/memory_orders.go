@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memOrders struct {
+	s *MemoryStore
+}
+
+// attachItems: เติมรายการสินค้าของคำสั่งซื้อ เรียงตาม id
+func attachItems(d *memData, order *models.Order) {
+	order.Items = nil
+	for _, item := range d.orderItems {
+		if item.OrderID == order.ID {
+			order.Items = append(order.Items, item)
+		}
+	}
+	sort.Slice(order.Items, func(i, j int) bool { return order.Items[i].ID < order.Items[j].ID })
+}
+
+func (r memOrders) Create(_ context.Context, order *models.Order) error {
+	return r.s.do(func(d *memData) error {
+		now := time.Now()
+		if order.Status == "" {
+			order.Status = models.OrderStatusPendingPayment
+		}
+		order.ID = d.nextID("orders")
+		order.CreatedAt, order.UpdatedAt = now, now
+		for i := range order.Items {
+			item := &order.Items[i]
+			item.ID = d.nextID("order_items")
+			item.OrderID = order.ID
+			item.CreatedAt, item.UpdatedAt = now, now
+			d.orderItems[item.ID] = *item
+		}
+		for i := range order.Transitions {
+			t := &order.Transitions[i]
+			t.ID = d.nextID("order_transitions")
+			t.OrderID = order.ID
+			t.CreatedAt, t.UpdatedAt = now, now
+			d.transitions[t.ID] = *t
+		}
+		stored := *order
+		stored.Items, stored.Transitions = nil, nil
+		d.orders[order.ID] = stored
+		return nil
+	})
+}
+
+func (r memOrders) ListByUser(_ context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) {
+	var orders []models.Order
+	err := r.s.do(func(d *memData) error {
+		for _, o := range d.orders {
+			if o.UserID == userID {
+				attachItems(d, &o)
+				orders = append(orders, o)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ใหม่ไปเก่า (เวลาเท่ากันเรียงตาม id มากไปน้อย)
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID > orders[j].ID
+	})
+	total := int64(len(orders))
+	if offset >= len(orders) {
+		return nil, total, nil
+	}
+	orders = orders[offset:]
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, total, nil
+}
+
+func (r memOrders) GetForUser(_ context.Context, userID, id uint) (*models.Order, error) {
+	var order *models.Order
+	err := r.s.do(func(d *memData) error {
+		o, ok := d.orders[id]
+		if !ok || o.UserID != userID {
+			return ErrNotFound
+		}
+		attachItems(d, &o)
+		order = &o
+		return nil
+	})
+	return order, err
+}
+
+func (r memOrders) Get(_ context.Context, id uint) (*models.Order, error) {
+	var order *models.Order
+	err := r.s.do(func(d *memData) error {
+		o, ok := d.orders[id]
+		if !ok {
+			return ErrNotFound
+		}
+		attachItems(d, &o)
+		for _, t := range d.transitions {
+			if t.OrderID == id {
+				o.Transitions = append(o.Transitions, t)
+			}
+		}
+		sort.Slice(o.Transitions, func(i, j int) bool { return o.Transitions[i].ID < o.Transitions[j].ID })
+		order = &o
+		return nil
+	})
+	return order, err
+}
+
+func (r memOrders) LockForUpdate(_ context.Context, id uint) (*models.Order, error) {
+	var order *models.Order
+	err := r.s.do(func(d *memData) error {
+		o, ok := d.orders[id]
+		if !ok {
+			return ErrNotFound
+		}
+		order = &o
+		return nil
+	})
+	return order, err
+}
+
+func (r memOrders) Items(_ context.Context, orderID uint) ([]models.OrderItem, error) {
+	var items []models.OrderItem
+	err := r.s.do(func(d *memData) error {
+		o := models.Order{}
+		o.ID = orderID
+		attachItems(d, &o)
+		items = o.Items
+		return nil
+	})
+	return items, err
+}
+
+func (r memOrders) UpdateStatus(_ context.Context, id uint, status string) error {
+	return r.s.do(func(d *memData) error {
+		o, ok := d.orders[id]
+		if !ok {
+			return nil
+		}
+		o.Status = status
+		o.UpdatedAt = time.Now()
+		d.orders[id] = o
+		return nil
+	})
+}
+
+func (r memOrders) AddTransition(_ context.Context, t *models.OrderTransition) error {
+	return r.s.do(func(d *memData) error {
+		now := time.Now()
+		t.ID = d.nextID("order_transitions")
+		t.CreatedAt, t.UpdatedAt = now, now
+		d.transitions[t.ID] = *t
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memPayments struct {
+	s *MemoryStore
+}
+
+func (r memPayments) Create(_ context.Context, p *models.Payment) error {
+	return r.s.do(func(d *memData) error {
+		for _, existing := range d.payments {
+			if existing.IntentID == p.IntentID {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		p.ID = d.nextID("payments")
+		p.CreatedAt, p.UpdatedAt = now, now
+		d.payments[p.ID] = *p
+		return nil
+	})
+}
+
+func (r memPayments) GetByIntentForUpdate(_ context.Context, intentID string) (*models.Payment, error) {
+	var payment *models.Payment
+	err := r.s.do(func(d *memData) error {
+		for _, p := range d.payments {
+			if p.IntentID == intentID {
+				payment = &p
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return payment, err
+}
+
+func (r memPayments) IntentExists(ctx context.Context, intentID string) (bool, error) {
+	_, err := r.GetByIntentForUpdate(ctx, intentID)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r memPayments) FindByOrderStatus(_ context.Context, orderID uint, status string) (*models.Payment, error) {
+	var payment *models.Payment
+	err := r.s.do(func(d *memData) error {
+		for _, p := range d.payments {
+			if p.OrderID == orderID && p.Status == status && (payment == nil || p.ID < payment.ID) {
+				found := p
+				payment = &found
+			}
+		}
+		if payment == nil {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return payment, err
+}
+
+func (r memPayments) UpdateStatus(_ context.Context, id uint, status string) error {
+	return r.s.do(func(d *memData) error {
+		p, ok := d.payments[id]
+		if !ok {
+			return nil
+		}
+		p.Status = status
+		p.UpdatedAt = time.Now()
+		d.payments[id] = p
+		return nil
+	})
+}
+
+func (r memPayments) RecordEvent(_ context.Context, e *models.PaymentEvent) (bool, error) {
+	recorded := false
+	err := r.s.do(func(d *memData) error {
+		if _, seen := d.events[e.EventID]; seen {
+			return nil
+		}
+		now := time.Now()
+		e.ID = d.nextID("payment_events")
+		e.CreatedAt, e.UpdatedAt = now, now
+		d.events[e.EventID] = *e
+		recorded = true
+		return nil
+	})
+	return recorded, err
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memReservations struct {
+	s *MemoryStore
+}
+
+// reservedStock: ยอดจองรวมของหนังสือเล่มนี้
+func (d *memData) reservedStock(bookID uint) int {
+	total := 0
+	for _, res := range d.reserved {
+		if res.BookID == bookID {
+			total += res.Quantity
+		}
+	}
+	return total
+}
+
+func (r memReservations) Create(_ context.Context, res *models.StockReservation) error {
+	return r.s.do(func(d *memData) error {
+		res.ID = d.nextID("stock_reservations")
+		res.CreatedAt = time.Now()
+		d.reserved[res.ID] = *res
+		return nil
+	})
+}
+
+func (r memReservations) Reserved(_ context.Context, bookIDs []uint) (map[uint]int, error) {
+	reserved := map[uint]int{}
+	err := r.s.do(func(d *memData) error {
+		for _, id := range bookIDs {
+			if total := d.reservedStock(id); total > 0 {
+				reserved[id] = total
+			}
+		}
+		return nil
+	})
+	return reserved, err
+}
+
+func (r memReservations) ListByOrder(_ context.Context, orderID uint) ([]models.StockReservation, error) {
+	var items []models.StockReservation
+	err := r.s.do(func(d *memData) error {
+		for _, res := range d.reserved {
+			if res.OrderID == orderID {
+				items = append(items, res)
+			}
+		}
+		return nil
+	})
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, err
+}
+
+func (r memReservations) DeleteByOrder(_ context.Context, orderID uint) error {
+	return r.s.do(func(d *memData) error {
+		for id, res := range d.reserved {
+			if res.OrderID == orderID {
+				delete(d.reserved, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (r memReservations) ExpiredOrders(_ context.Context, now time.Time, limit int) ([]uint, error) {
+	earliest := map[uint]time.Time{}
+	err := r.s.do(func(d *memData) error {
+		for _, res := range d.reserved {
+			if !res.ExpiresAt.Before(now) {
+				continue
+			}
+			if at, ok := earliest[res.OrderID]; !ok || res.ExpiresAt.Before(at) {
+				earliest[res.OrderID] = res.ExpiresAt
+			}
+		}
+		return nil
+	})
+	ids := make([]uint, 0, len(earliest))
+	for id := range earliest {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if !earliest[ids[i]].Equal(earliest[ids[j]]) {
+			return earliest[ids[i]].Before(earliest[ids[j]])
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memSettings struct {
+	s *MemoryStore
+}
+
+func (r memSettings) List(_ context.Context) ([]models.Setting, error) {
+	var settings []models.Setting
+	err := r.s.do(func(d *memData) error {
+		for _, s := range d.settings {
+			settings = append(settings, s)
+		}
+		sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+		return nil
+	})
+	return settings, err
+}
+
+func (r memSettings) Get(_ context.Context, key string) (string, error) {
+	var value string
+	err := r.s.do(func(d *memData) error {
+		s, ok := d.settings[key]
+		if !ok {
+			return ErrNotFound
+		}
+		value = s.Value
+		return nil
+	})
+	return value, err
+}
+
+func (r memSettings) Set(_ context.Context, key, value string) error {
+	return r.s.do(func(d *memData) error {
+		d.settings[key] = models.Setting{Key: key, Value: value, UpdatedAt: time.Now()}
+		return nil
+	})
+}
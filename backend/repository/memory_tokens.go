@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memTokens struct {
+	s *MemoryStore
+}
+
+func (r memTokens) Create(_ context.Context, t *models.RefreshToken) error {
+	return r.s.do(func(d *memData) error {
+		for _, existing := range d.tokens {
+			if existing.TokenHash == t.TokenHash {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		t.ID = d.nextID("refresh_tokens")
+		t.CreatedAt, t.UpdatedAt = now, now
+		d.tokens[t.ID] = *t
+		return nil
+	})
+}
+
+func (r memTokens) GetByHashForUpdate(_ context.Context, hash string) (*models.RefreshToken, error) {
+	var token *models.RefreshToken
+	err := r.s.do(func(d *memData) error {
+		for _, t := range d.tokens {
+			if t.TokenHash == hash {
+				token = &t
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return token, err
+}
+
+func (r memTokens) MarkUsed(_ context.Context, id uint, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		if t, ok := d.tokens[id]; ok {
+			t.UsedAt = &at
+			t.UpdatedAt = at
+			d.tokens[id] = t
+		}
+		return nil
+	})
+}
+
+func (r memTokens) RevokeFamily(_ context.Context, familyID string, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		for id, t := range d.tokens {
+			if t.FamilyID == familyID && t.RevokedAt == nil {
+				t.RevokedAt = &at
+				t.UpdatedAt = at
+				d.tokens[id] = t
+			}
+		}
+		return nil
+	})
+}
+
+func (r memTokens) RevokeUser(_ context.Context, userID uint, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		for id, t := range d.tokens {
+			if t.UserID == userID && t.RevokedAt == nil {
+				t.RevokedAt = &at
+				t.UpdatedAt = at
+				d.tokens[id] = t
+			}
+		}
+		return nil
+	})
+}
+
+type memRevocations struct {
+	s *MemoryStore
+}
+
+func (r memRevocations) RevokeToken(_ context.Context, t *models.RevokedToken) error {
+	return r.s.do(func(d *memData) error {
+		if _, ok := d.revoked[t.JTI]; ok {
+			return nil
+		}
+		now := time.Now()
+		t.ID = d.nextID("revoked_tokens")
+		t.CreatedAt, t.UpdatedAt = now, now
+		d.revoked[t.JTI] = *t
+		return nil
+	})
+}
+
+func (r memRevocations) RevokeUser(_ context.Context, userID uint, before time.Time) error {
+	return r.s.do(func(d *memData) error {
+		d.sessions[userID] = before
+		return nil
+	})
+}
+
+func (r memRevocations) IsRevoked(_ context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	revoked := false
+	err := r.s.do(func(d *memData) error {
+		_, listed := d.revoked[jti]
+		before, ok := d.sessions[userID]
+		revoked = listed || (ok && before.After(issuedAt))
+		return nil
+	})
+	return revoked, err
+}
+
+func (r memRevocations) PurgeExpired(_ context.Context, now time.Time) error {
+	return r.s.do(func(d *memData) error {
+		for jti, t := range d.revoked {
+			if t.ExpiresAt.Before(now) {
+				delete(d.revoked, jti)
+			}
+		}
+		return nil
+	})
+}
+
+type memPasswordResets struct {
+	s *MemoryStore
+}
+
+func (r memPasswordResets) Create(_ context.Context, t *models.PasswordResetToken) error {
+	return r.s.do(func(d *memData) error {
+		for _, existing := range d.resets {
+			if existing.TokenHash == t.TokenHash {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		t.ID = d.nextID("password_reset_tokens")
+		t.CreatedAt, t.UpdatedAt = now, now
+		d.resets[t.ID] = *t
+		return nil
+	})
+}
+
+func (r memPasswordResets) GetByHashForUpdate(_ context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token *models.PasswordResetToken
+	err := r.s.do(func(d *memData) error {
+		for _, t := range d.resets {
+			if t.TokenHash == hash {
+				token = &t
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return token, err
+}
+
+func (r memPasswordResets) InvalidateUser(_ context.Context, userID uint, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		for id, t := range d.resets {
+			if t.UserID == userID && t.UsedAt == nil {
+				t.UsedAt = &at
+				t.UpdatedAt = at
+				d.resets[id] = t
+			}
+		}
+		return nil
+	})
+}
+
+func (r memPasswordResets) MarkUsed(_ context.Context, id uint, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		if t, ok := d.resets[id]; ok {
+			t.UsedAt = &at
+			t.UpdatedAt = at
+			d.resets[id] = t
+		}
+		return nil
+	})
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"my-fiber-app/models"
+)
+
+type memUsers struct {
+	s *MemoryStore
+}
+
+func (r memUsers) Create(_ context.Context, user *models.User) error {
+	return r.s.do(func(d *memData) error {
+		for _, u := range d.users {
+			if u.Email == user.Email {
+				return ErrDuplicate
+			}
+		}
+		if user.Role == "" {
+			user.Role = models.RoleUser // เหมือนค่า default ของคอลัมน์ role
+		}
+		now := time.Now()
+		user.ID = d.nextID("users")
+		user.CreatedAt, user.UpdatedAt = now, now
+		d.users[user.ID] = *user
+		return nil
+	})
+}
+
+func (r memUsers) Get(_ context.Context, id uint) (*models.User, error) {
+	var user *models.User
+	err := r.s.do(func(d *memData) error {
+		u, ok := d.users[id]
+		if !ok {
+			return ErrNotFound
+		}
+		user = &u
+		return nil
+	})
+	return user, err
+}
+
+func (r memUsers) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	var user *models.User
+	err := r.s.do(func(d *memData) error {
+		for _, u := range d.users {
+			if u.Email == email {
+				user = &u
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return user, err
+}
+
+func (r memUsers) List(_ context.Context, f UserFilter, limit, offset int) ([]models.User, int64, error) {
+	var users []models.User
+	err := r.s.do(func(d *memData) error {
+		query := strings.ToLower(f.Query)
+		for _, u := range d.users {
+			if query != "" && !strings.Contains(strings.ToLower(u.Email), query) && !strings.Contains(strings.ToLower(u.Name), query) {
+				continue
+			}
+			if f.Role != "" && u.Role != f.Role {
+				continue
+			}
+			if f.Suspended != nil && u.Suspended() != *f.Suspended {
+				continue
+			}
+			users = append(users, u)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ใหม่ไปเก่า (เวลาเท่ากันเรียงตาม id มากไปน้อย)
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.After(users[j].CreatedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+	total := int64(len(users))
+	if offset >= len(users) {
+		return nil, total, nil
+	}
+	users = users[offset:]
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, total, nil
+}
+
+func (r memUsers) UpdatePassword(_ context.Context, id uint, hash string) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok {
+			u.Password = hash
+			u.UpdatedAt = time.Now()
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) MarkEmailVerified(_ context.Context, id uint, at time.Time) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok && u.EmailVerifiedAt == nil {
+			u.EmailVerifiedAt = &at
+			u.UpdatedAt = at
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) SetLanguage(_ context.Context, id uint, language string) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok {
+			u.Language = language
+			u.UpdatedAt = time.Now()
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) UpdateRole(_ context.Context, id uint, role string) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok {
+			u.Role = role
+			u.UpdatedAt = time.Now()
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) Suspend(_ context.Context, id uint, at time.Time, reason string) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok {
+			u.SuspendedAt = &at
+			u.SuspendReason = reason
+			u.UpdatedAt = at
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) Unsuspend(_ context.Context, id uint) error {
+	return r.s.do(func(d *memData) error {
+		if u, ok := d.users[id]; ok {
+			u.SuspendedAt = nil
+			u.SuspendReason = ""
+			u.UpdatedAt = time.Now()
+			d.users[id] = u
+		}
+		return nil
+	})
+}
+
+func (r memUsers) GetRole(_ context.Context, name string) (*models.Role, error) {
+	var role *models.Role
+	err := r.s.do(func(d *memData) error {
+		ro, ok := d.roles[name]
+		if !ok {
+			return ErrNotFound
+		}
+		role = &ro
+		return nil
+	})
+	return role, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"my-fiber-app/models"
+	"my-fiber-app/search"
+)
+
+// textRank: คะแนนการค้นหาแบบหาคำในข้อความ (ใช้แทน Full-text ในที่ที่ไม่มี tsvector)
+// ทุกคำต้องพบในอย่างน้อยหนึ่ง Field น้ำหนัก Title 1.0, Author 0.4, Description 0.2
+func textRank(b models.Book, terms []search.Term) (float64, bool) {
+	title, author, desc := strings.ToLower(b.Title), strings.ToLower(b.Author), strings.ToLower(b.Description)
+	rank := 0.0
+	for _, t := range terms {
+		hit := false
+		for _, f := range []struct {
+			text   string
+			weight float64
+		}{{title, 1.0}, {author, 0.4}, {desc, 0.2}} {
+			if strings.Contains(f.text, t.Text) {
+				rank += f.weight
+				hit = true
+			}
+		}
+		if !hit {
+			return 0, false
+		}
+	}
+	return rank, true
+}
+
+// similarityRank: ความคล้ายแบบ Trigram เหมือนเงื่อนไขใน gormBooks.SearchSimilar ของ PostgreSQL
+func similarityRank(b models.Book, text string) float64 {
+	return max(trigramSimilarity(b.Title, text), trigramWordSimilarity(text, b.Title), trigramSimilarity(b.Author, text))
+}
+
+// rankAndLimit: เรียงตามคะแนนมากไปน้อย (เท่ากันเรียงตาม id) แล้วตัดตามจำนวนที่ขอ
+func rankAndLimit(matches []BookMatch, limit int) []BookMatch {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return matches[i].Book.ID < matches[j].Book.ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
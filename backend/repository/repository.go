@@ -0,0 +1,288 @@
+// Package repository: ชั้นเข้าถึงข้อมูลของระบบ
+// Handler คุยกับฐานข้อมูลผ่าน Interface ในไฟล์นี้เท่านั้น
+// มีสองแบบ: GORM (ใช้งานจริง) และ Memory (ทดสอบโดยไม่ต้องมี PostgreSQL)
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"my-fiber-app/models"
+	"my-fiber-app/search"
+)
+
+var (
+	// ErrNotFound: ไม่พบข้อมูลที่ต้องการ
+	ErrNotFound = errors.New("repository: record not found")
+	// ErrDuplicate: ข้อมูลซ้ำกับที่มีอยู่แล้ว (ชนกับ Unique Constraint)
+	ErrDuplicate = errors.New("repository: duplicate record")
+	// ErrStockExceeded: จำนวนในตะกร้าจะเกินสต็อกที่ขายได้ของหนังสือ (สต็อกลบยอดที่ถูกจองไว้)
+	ErrStockExceeded = errors.New("repository: quantity exceeds stock")
+)
+
+// Store: รวม Repository ทั้งหมด และเปิด Transaction ได้
+type Store interface {
+	Books() BookRepository
+	Users() UserRepository
+	Carts() CartRepository
+	GuestCarts() GuestCartRepository
+	Orders() OrderRepository
+	Reservations() ReservationRepository
+	StockMovements() StockMovementRepository
+	Payments() PaymentRepository
+	Tokens() TokenRepository
+	Revocations() RevocationRepository
+	PasswordResets() PasswordResetRepository
+	Settings() SettingRepository
+	AuditLogs() AuditLogRepository
+
+	// Transaction: รัน fn ด้วย Store ที่ผูกกับ Transaction เดียวกัน
+	// ถ้า fn คืน error ทุกอย่างที่ทำใน fn จะถูกยกเลิก
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
+
+// BookFilter: ตัวกรองรายการหนังสือ
+type BookFilter struct {
+	Author   string // ค้นแบบมีคำนี้อยู่ ไม่สนตัวพิมพ์เล็ก/ใหญ่
+	MinPrice *int
+	MaxPrice *int
+	InStock  bool // สต็อกที่ขายได้ (ลบยอดที่ถูกจองไว้) มากกว่า 0
+}
+
+// BookKey: ตำแหน่งของหนังสือในลำดับการเรียง (สำหรับการแบ่งหน้าแบบ Keyset)
+type BookKey struct {
+	Value interface{} // ค่าของคอลัมน์ที่ใช้เรียง (int, string หรือ time.Time)
+	ID    uint
+}
+
+// BookQuery: เงื่อนไขการดึงรายการหนังสือ
+type BookQuery struct {
+	BookFilter
+	SortBy string   // "price", "title" หรือ "created_at" (เรียงรองด้วย id เสมอ)
+	Desc   bool     // ทิศทางการสแกน
+	After  *BookKey // ถ้ากำหนด ดึงเฉพาะรายการที่อยู่ถัดจากตำแหน่งนี้ในทิศทางการสแกน
+	Offset int
+	Limit  int
+}
+
+// BookMatch: หนังสือที่ตรงกับการค้นหา พร้อมคะแนน
+type BookMatch struct {
+	Book models.Book
+	Rank float64
+}
+
+// BookRepository: การเข้าถึงข้อมูลหนังสือ
+type BookRepository interface {
+	List(ctx context.Context, q BookQuery) ([]models.Book, error)
+	Count(ctx context.Context, f BookFilter) (int64, error)
+	// Search: ค้นด้วย Full-text (ทุกคำต้องตรง) เรียงตามคะแนน
+	Search(ctx context.Context, terms []search.Term, limit int) ([]BookMatch, error)
+	// SearchSimilar: ค้นด้วยความคล้ายของตัวอักษร (Trigram) สำหรับคำสะกดผิด
+	SearchSimilar(ctx context.Context, text string, threshold float64, limit int) ([]BookMatch, error)
+	Get(ctx context.Context, id uint) (*models.Book, error)
+	// LockForUpdate: ดึงหนังสือหลายเล่มพร้อมล็อกแถว (ใช้ภายใน Transaction) เรียงตาม id
+	LockForUpdate(ctx context.Context, ids []uint) ([]models.Book, error)
+	Create(ctx context.Context, book *models.Book) error
+	// Update: บันทึกทุก Field ที่แก้ไขได้ (รวมค่า 0 และค่าว่าง) ยกเว้นสต็อก
+	Update(ctx context.Context, book *models.Book) error
+	Delete(ctx context.Context, id uint) error
+	// AdjustStock: เพิ่ม/ลดสต็อกแบบ Atomic (delta ติดลบ = ตัดสต็อก)
+	// เรียกคู่กับการบันทึก StockMovement ใน Transaction เดียวกันเสมอ สต็อกจึงตรงกับสมุดบัญชี
+	AdjustStock(ctx context.Context, id uint, delta int) error
+}
+
+// UserFilter: ตัวกรองรายชื่อผู้ใช้ (สำหรับผู้ดูแล)
+type UserFilter struct {
+	Query     string // ค้นแบบมีคำนี้อยู่ในอีเมลหรือชื่อ ไม่สนตัวพิมพ์เล็ก/ใหญ่
+	Role      string
+	Suspended *bool // nil = ทุกบัญชี
+}
+
+// UserRepository: การเข้าถึงข้อมูลผู้ใช้และบทบาท
+type UserRepository interface {
+	// Create: คืน ErrDuplicate ถ้าอีเมลนี้มีอยู่แล้ว
+	Create(ctx context.Context, user *models.User) error
+	Get(ctx context.Context, id uint) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// List: ผู้ใช้ที่ตรงกับตัวกรอง (ใหม่ไปเก่า) และจำนวนทั้งหมด
+	List(ctx context.Context, f UserFilter, limit, offset int) ([]models.User, int64, error)
+	// UpdatePassword: เปลี่ยนรหัสผ่าน (ค่าที่ส่งมาต้อง Hash แล้ว)
+	UpdatePassword(ctx context.Context, id uint, hash string) error
+	// MarkEmailVerified: บันทึกเวลายืนยันอีเมล (ถ้ายืนยันไว้แล้ว ไม่เปลี่ยนเวลาเดิม)
+	MarkEmailVerified(ctx context.Context, id uint, at time.Time) error
+	// SetLanguage: บันทึกภาษาที่ผู้ใช้เลือก (ค่าว่าง = กลับไปใช้ Accept-Language)
+	SetLanguage(ctx context.Context, id uint, language string) error
+	// UpdateRole: เปลี่ยนบทบาท (ตรวจว่ามีบทบาทนี้อยู่จริงก่อนเรียก)
+	UpdateRole(ctx context.Context, id uint, role string) error
+	// Suspend: ระงับบัญชีพร้อมเหตุผล
+	Suspend(ctx context.Context, id uint, at time.Time, reason string) error
+	// Unsuspend: ยกเลิกการระงับบัญชี
+	Unsuspend(ctx context.Context, id uint) error
+	// GetRole: ดึงบทบาทพร้อมสิทธิ์ทั้งหมด
+	GetRole(ctx context.Context, name string) (*models.Role, error)
+}
+
+// CartRepository: การเข้าถึงตะกร้าสินค้า (ทุกเมธอดจำกัดเฉพาะของผู้ใช้ที่ระบุ)
+type CartRepository interface {
+	// List: รายการในตะกร้าพร้อมข้อมูลหนังสือ
+	List(ctx context.Context, userID uint) ([]models.CartItem, error)
+	// FindByBook: รายการของหนังสือเล่มนี้ในตะกร้า (ถ้ามี)
+	FindByBook(ctx context.Context, userID, bookID uint) (*models.CartItem, error)
+	Get(ctx context.Context, userID, itemID uint) (*models.CartItem, error)
+	// Add: เพิ่มหนังสือลงตะกร้า ถ้ามีอยู่แล้วให้บวกเพิ่ม (ทำในคำสั่งเดียว Request ที่มาพร้อมกันไม่ทับกัน)
+	// คืน ErrNotFound ถ้าไม่มีหนังสือ และ ErrStockExceeded ถ้าจำนวนรวมในตะกร้าจะเกินสต็อกที่ขายได้ (ไม่มีอะไรเปลี่ยน)
+	Add(ctx context.Context, userID, bookID uint, quantity int) (*models.CartItem, error)
+	Save(ctx context.Context, item *models.CartItem) error
+	// Delete: ลบจริง (ไม่ใช่ Soft Delete) คืน ErrNotFound ถ้าไม่มีรายการนี้ในตะกร้าของผู้ใช้
+	Delete(ctx context.Context, userID, itemID uint) error
+	Clear(ctx context.Context, userID uint) error
+}
+
+// GuestCartRepository: ตะกร้าของผู้ที่ยังไม่ได้เข้าสู่ระบบ (ทุกเมธอดจำกัดเฉพาะตะกร้าที่ระบุ)
+type GuestCartRepository interface {
+	// Touch: สร้างตะกร้า (ถ้ายังไม่มี) หรือบันทึกเวลาใช้งานล่าสุด
+	Touch(ctx context.Context, cartID string, at time.Time) error
+	// List: รายการในตะกร้าพร้อมข้อมูลหนังสือ
+	List(ctx context.Context, cartID string) ([]models.GuestCartItem, error)
+	// FindByBook: รายการของหนังสือเล่มนี้ในตะกร้า (ถ้ามี)
+	FindByBook(ctx context.Context, cartID string, bookID uint) (*models.GuestCartItem, error)
+	Get(ctx context.Context, cartID string, itemID uint) (*models.GuestCartItem, error)
+	CreateItem(ctx context.Context, item *models.GuestCartItem) error
+	SaveItem(ctx context.Context, item *models.GuestCartItem) error
+	// DeleteItem: คืน ErrNotFound ถ้าไม่มีรายการนี้ในตะกร้า
+	DeleteItem(ctx context.Context, cartID string, itemID uint) error
+	// Delete: ลบตะกร้าพร้อมทุกรายการ (ไม่มีตะกร้านี้ก็ไม่ Error)
+	Delete(ctx context.Context, cartID string) error
+	// PurgeStale: ลบตะกร้าที่ไม่ได้ใช้งานตั้งแต่ก่อนเวลา before
+	PurgeStale(ctx context.Context, before time.Time) error
+}
+
+// OrderRepository: การเข้าถึงคำสั่งซื้อ
+type OrderRepository interface {
+	// Create: บันทึกคำสั่งซื้อพร้อม Items และ Transitions ที่แนบมา
+	Create(ctx context.Context, order *models.Order) error
+	// ListByUser: คำสั่งซื้อของผู้ใช้ (ใหม่ไปเก่า) พร้อมรายการสินค้า และจำนวนทั้งหมด
+	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error)
+	// GetForUser: คำสั่งซื้อพร้อมรายการสินค้า เฉพาะของเจ้าของ
+	GetForUser(ctx context.Context, userID, id uint) (*models.Order, error)
+	// Get: คำสั่งซื้อพร้อมรายการสินค้าและประวัติสถานะ (เรียงตามเวลา)
+	Get(ctx context.Context, id uint) (*models.Order, error)
+	// LockForUpdate: ดึงคำสั่งซื้อพร้อมล็อกแถว (ใช้ภายใน Transaction)
+	LockForUpdate(ctx context.Context, id uint) (*models.Order, error)
+	Items(ctx context.Context, orderID uint) ([]models.OrderItem, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	AddTransition(ctx context.Context, t *models.OrderTransition) error
+}
+
+// ReservationRepository: การจองสต็อกของคำสั่งซื้อที่รอชำระเงิน
+type ReservationRepository interface {
+	Create(ctx context.Context, r *models.StockReservation) error
+	// Reserved: ยอดจองรวมของหนังสือแต่ละเล่ม (เล่มที่ไม่มีการจองไม่อยู่ใน Map)
+	Reserved(ctx context.Context, bookIDs []uint) (map[uint]int, error)
+	ListByOrder(ctx context.Context, orderID uint) ([]models.StockReservation, error)
+	// DeleteByOrder: ปล่อยการจองทั้งหมดของคำสั่งซื้อ (ไม่มีการจองก็ไม่ Error)
+	DeleteByOrder(ctx context.Context, orderID uint) error
+	// ExpiredOrders: คำสั่งซื้อที่มีการจองหมดอายุก่อนเวลา now (เก่าไปใหม่ ไม่เกิน limit รายการ)
+	ExpiredOrders(ctx context.Context, now time.Time, limit int) ([]uint, error)
+}
+
+// StockMovementFilter: ตัวกรองสมุดบัญชีสต็อก (ค่าว่าง = ไม่กรอง)
+type StockMovementFilter struct {
+	BookID uint
+	Type   string
+}
+
+// StockMovementRepository: สมุดบัญชีสต็อก (เพิ่มได้อย่างเดียว)
+type StockMovementRepository interface {
+	Create(ctx context.Context, m *models.StockMovement) error
+	// List: รายการที่ตรงกับตัวกรอง (ใหม่ไปเก่า) และจำนวนทั้งหมด
+	List(ctx context.Context, f StockMovementFilter, limit, offset int) ([]models.StockMovement, int64, error)
+	// Total: ผลรวมของทุกรายการของหนังสือเล่มนี้ (ต้องเท่ากับ Book.Stock)
+	Total(ctx context.Context, bookID uint) (int, error)
+}
+
+// PaymentRepository: การเข้าถึงข้อมูลการชำระเงินและ Webhook
+type PaymentRepository interface {
+	Create(ctx context.Context, p *models.Payment) error
+	// GetByIntentForUpdate: ดึงการชำระเงินจาก Intent ID พร้อมล็อกแถว
+	GetByIntentForUpdate(ctx context.Context, intentID string) (*models.Payment, error)
+	IntentExists(ctx context.Context, intentID string) (bool, error)
+	// FindByOrderStatus: การชำระเงินของคำสั่งซื้อที่อยู่ในสถานะที่ระบุ
+	FindByOrderStatus(ctx context.Context, orderID uint, status string) (*models.Payment, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	// RecordEvent: บันทึก Webhook คืน false ถ้า EventID นี้เคยบันทึกแล้ว (Replay)
+	RecordEvent(ctx context.Context, e *models.PaymentEvent) (bool, error)
+}
+
+// TokenRepository: Refresh Token (ค้นหาด้วยค่า Hash เท่านั้น)
+type TokenRepository interface {
+	Create(ctx context.Context, t *models.RefreshToken) error
+	// GetByHashForUpdate: ดึง Token จากค่า Hash พร้อมล็อกแถว (ใช้ภายใน Transaction)
+	GetByHashForUpdate(ctx context.Context, hash string) (*models.RefreshToken, error)
+	MarkUsed(ctx context.Context, id uint, at time.Time) error
+	// RevokeFamily: เพิกถอนทุก Token ใน Family ที่ยังไม่ถูกเพิกถอน
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeUser: เพิกถอน Refresh Token ทุกตัวของผู้ใช้ที่ยังไม่ถูกเพิกถอน
+	RevokeUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+// RevocationRepository: Denylist ของ Access Token ที่ JWT Middleware ตรวจทุกคำขอ
+type RevocationRepository interface {
+	// RevokeToken: เพิกถอน Token เดียว (ซ้ำได้ ไม่ Error)
+	RevokeToken(ctx context.Context, t *models.RevokedToken) error
+	// RevokeUser: เพิกถอนทุก Token ของผู้ใช้ที่ออกก่อนเวลา before
+	RevokeUser(ctx context.Context, userID uint, before time.Time) error
+	// IsRevoked: Token (jti) ของผู้ใช้ที่ออกเมื่อ issuedAt ถูกเพิกถอนแล้วหรือไม่
+	IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error)
+	// PurgeExpired: ลบรายการของ Token ที่หมดอายุไปแล้ว (ไม่ต้องจำอีกต่อไป)
+	PurgeExpired(ctx context.Context, now time.Time) error
+}
+
+// PasswordResetRepository: Token รีเซ็ตรหัสผ่าน (ค้นหาด้วยค่า Hash เท่านั้น)
+type PasswordResetRepository interface {
+	Create(ctx context.Context, t *models.PasswordResetToken) error
+	// GetByHashForUpdate: ดึง Token จากค่า Hash พร้อมล็อกแถว (ใช้ภายใน Transaction)
+	GetByHashForUpdate(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	// InvalidateUser: ปิดทุก Token ของผู้ใช้ที่ยังไม่ถูกใช้ (ให้ลิงก์ล่าสุดเท่านั้นที่ใช้ได้)
+	InvalidateUser(ctx context.Context, userID uint, at time.Time) error
+	MarkUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+// SettingRepository: การตั้งค่าระบบแบบ Key/Value
+type SettingRepository interface {
+	// List: ค่าที่ถูกตั้งไว้แล้วทั้งหมด (ไม่รวมค่าเริ่มต้นที่ยังไม่เคยตั้ง)
+	List(ctx context.Context) ([]models.Setting, error)
+	// Get: คืน ErrNotFound ถ้ายังไม่เคยตั้งค่านี้
+	Get(ctx context.Context, key string) (string, error)
+	// Set: ตั้งค่า (สร้างใหม่หรือแทนที่ค่าเดิม)
+	Set(ctx context.Context, key, value string) error
+}
+
+// AuditLogFilter: ตัวกรองบันทึกการกระทำของผู้ดูแล (ค่าศูนย์/ค่าว่าง = ไม่กรอง)
+type AuditLogFilter struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+}
+
+// AuditLogRepository: บันทึกการกระทำของผู้ดูแล (เพิ่มและอ่านได้อย่างเดียว)
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	// List: บันทึกที่ตรงกับตัวกรอง (ใหม่ไปเก่า) และจำนวนทั้งหมด
+	List(ctx context.Context, f AuditLogFilter, limit, offset int) ([]models.AuditLog, int64, error)
+}
+
+// SettingBool: อ่านการตั้งค่าแบบ Boolean ใช้ค่าใน models.DefaultSettings ถ้ายังไม่เคยตั้ง
+func SettingBool(ctx context.Context, settings SettingRepository, key string) (bool, error) {
+	value, err := settings.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		value, err = models.DefaultSettings[key], nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// trigrams: ชุด Trigram ของข้อความตามแบบ pg_trgm
+// (ตัวพิมพ์เล็ก แยกเป็นคำ แต่ละคำเติมช่องว่างหน้า 2 ตัวและหลัง 1 ตัว)
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// trigramSimilarity: ใกล้เคียงกับ similarity() ของ pg_trgm (จำนวนที่ร่วมกัน / จำนวนรวม)
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// trigramWordSimilarity: ใกล้เคียงกับ word_similarity() ของ pg_trgm
+// (สัดส่วน Trigram ของคำค้นที่พบในข้อความ)
+func trigramWordSimilarity(query, text string) float64 {
+	tq, tt := trigrams(query), trigrams(text)
+	if len(tq) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range tq {
+		if tt[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(tq))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	jwtware "github.com/gofiber/contrib/jwt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"my-fiber-app/apperr"
+	"my-fiber-app/handlers"
+	"my-fiber-app/mail"
+	"my-fiber-app/middleware"
+	"my-fiber-app/models"
+	"my-fiber-app/payments"
+	"my-fiber-app/repository"
+)
+
+// appConfig: ค่าการตั้งค่าที่ newApp ใช้ (main อ่านจาก Environment การทดสอบกำหนดเอง)
+type appConfig struct {
+	FrontendURL    string        // Origin ของเว็บหน้าบ้าน ใช้กับ CORS และลิงก์ในอีเมล
+	JWTSecret      string        // กุญแจเซ็นและตรวจ Access Token และต้นทางของกุญแจลิงก์ยืนยันอีเมลและตะกร้า Guest
+	PromptPayID    string        // หมายเลข PromptPay ของร้าน
+	ReservationTTL time.Duration // เวลาที่ Checkout จองสต็อกไว้ให้ชำระเงิน
+	FakeCapture    bool          // เปิด Route จำลองการชำระเงิน (PAYMENT_FAKE_CAPTURE, Development เท่านั้น)
+}
+
+// newApp: สร้างแอป Fiber พร้อม Middleware และ Routes ทั้งหมดบนที่เก็บข้อมูลที่ให้มา
+// แยกจาก main เพื่อให้ Integration Test เรียกแอปตัวเดียวกับเซิร์ฟเวอร์จริงผ่าน app.Test ได้
+func newApp(store repository.Store, gateway payments.Gateway, mailer mail.Mailer, cfg appConfig) *fiber.App {
+	// 1. สร้าง Handler โดยส่งที่เก็บข้อมูลและผู้ให้บริการชำระเงินเข้าไป
+	authHandler := handlers.NewAuthHandler(store, mailer, cfg.FrontendURL, cfg.JWTSecret)
+	bookHandler := handlers.NewBookHandler(store)
+	cartHandler := handlers.NewCartHandler(store.Carts(), store.Books(), store.Reservations())
+	guestCartHandler := handlers.NewGuestCartHandler(store, cfg.JWTSecret)
+	inventoryHandler := handlers.NewInventoryHandler(store)
+	orderHandler := handlers.NewOrderHandler(store, gateway, cfg.ReservationTTL)
+	paymentHandler := handlers.NewPaymentHandler(store, gateway, cfg.PromptPayID)
+	passwordHandler := handlers.NewPasswordHandler(store, mailer, cfg.FrontendURL)
+	settingsHandler := handlers.NewSettingsHandler(store.Settings())
+	userHandler := handlers.NewUserHandler(store, mailer, cfg.FrontendURL)
+	requirePermission := func(permission string) fiber.Handler {
+		return middleware.RequirePermission(store.Users(), permission)
+	}
+	// บัญชีที่ถูกระงับใช้ Token ที่ยังไม่หมดอายุต่อไม่ได้ (ใช้คู่กับ jwtMiddleware เสมอ)
+	activeUser := middleware.ActiveUser(store.Users())
+
+	// 2. เริ่มต้นสร้างแอปพลิเคชัน Fiber
+	// Error ทุกตัวที่ Handler คืนมาจะถูกแปลงเป็น application/problem+json (RFC 7807) ที่จุดเดียว
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+	})
+
+	// 3. ตั้งค่า Middleware ต่างๆ
+	// Request ID: ใช้ X-Request-ID ที่ส่งมา หรือสร้างใหม่ แล้วส่งกลับใน Header และในทุกคำตอบข้อผิดพลาด
+	app.Use(requestid.New(requestid.Config{
+		Generator: utils.UUIDv4, // สุ่มทั้งหมด ไม่เปิดเผยจำนวน Request เหมือนค่าเริ่มต้น
+	}))
+
+	// Language: เลือกภาษาของข้อความ (ไทย/อังกฤษ) จาก Accept-Language
+	app.Use(middleware.Language())
+
+	// Recover: Handler ที่ Panic จะได้คำตอบ 500 แบบ problem+json แทนการทำให้เซิร์ฟเวอร์ล่ม
+	app.Use(recover.New())
+
+	// CORS: อนุญาตให้เว็บหน้าบ้าน (Frontend) รับส่งข้อมูลกับ API
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:  cfg.FrontendURL,
+		AllowMethods:  "GET,POST,HEAD,PUT,DELETE,PATCH",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-Request-ID, X-Guest-Cart",
+		ExposeHeaders: "X-Request-ID, X-Guest-Cart",
+	}))
+
+	// Logger: ปริ้น Log การเรียกใช้งาน API ลงใน Terminal (พร้อม Request ID ไว้จับคู่กับคำตอบข้อผิดพลาด)
+	app.Use(logger.New(logger.Config{
+		Format: "[${time}] ${status} - ${latency} ${method} ${path} ${locals:requestid}\n",
+	}))
+
+	// 4. กำหนดเส้นทาง API (Routes)
+
+	// --- โซนสาธารณะ (Public): ไม่ต้องล็อกอิน ---
+	app.Get("/books", bookHandler.GetBooks)
+	app.Get("/books/search", bookHandler.SearchBooks)
+	app.Get("/books/:id", bookHandler.GetBook)
+	app.Post("/signup", authHandler.SignUp)
+	app.Post("/login", authHandler.Login)
+	app.Post("/token/refresh", authHandler.RefreshToken)
+	app.Post("/password/forgot", passwordHandler.ForgotPassword)
+	app.Post("/password/reset", passwordHandler.ResetPassword)
+	app.Post("/email/verify", authHandler.VerifyEmail)
+
+	// ตะกร้าของผู้ที่ยังไม่ได้เข้าสู่ระบบ (อ้างอิงด้วย Cookie guest_cart หรือ Header X-Guest-Cart)
+	// รวมเข้าตะกร้าของผู้ใช้อัตโนมัติตอน /login หรือ /signup
+	app.Get("/guest-cart", guestCartHandler.GetCart)
+	app.Post("/guest-cart", guestCartHandler.AddToCart)
+	app.Put("/guest-cart/:id", guestCartHandler.UpdateCartItem)
+	app.Delete("/guest-cart/:id", guestCartHandler.DeleteCartItem)
+
+	// Webhook จากผู้ให้บริการรับชำระเงิน (ยืนยันตัวตนด้วยลายเซ็น ไม่ใช่ JWT)
+	app.Post("/payments/webhook", paymentHandler.PaymentWebhook)
+	// Route จำลองการชำระเงินคืน Webhook ที่เซ็นแล้ว ใครเรียกได้ก็ทำให้คำสั่งซื้อเป็น paid ได้
+	// จึงเปิดเฉพาะเมื่อใช้ผู้ให้บริการจำลองและเปิด PAYMENT_FAKE_CAPTURE=true ไว้ (สำหรับ Development เท่านั้น)
+	if gateway.Name() == payments.ProviderFake && cfg.FakeCapture {
+		log.Println("⚠️  PAYMENT_FAKE_CAPTURE เปิดอยู่: ห้ามใช้บน Production")
+		app.Post("/payments/fake/intents/:id/capture", paymentHandler.FakeCapturePayment)
+	}
+
+	// --- ตั้งค่าระบบตรวจสอบบัตรผ่าน (JWT Middleware) ---
+	jwtMiddleware := jwtware.New(jwtware.Config{
+		SigningKey: jwtware.SigningKey{Key: []byte(cfg.JWTSecret)},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return apperr.ErrUnauthorized.Wrap(err)
+		},
+		// Token ที่ถูกเพิกถอน (ออกจากระบบแล้ว) ใช้ไม่ได้แม้ยังไม่หมดอายุ
+		SuccessHandler: middleware.RejectRevoked(store.Revocations()),
+	})
+
+	// --- โซนหวงห้าม (Private): ต้องล็อกอินก่อนเข้าถึง ---
+
+	// กลุ่มผู้จัดการระบบ (Admin): จัดการคลังหนังสือ
+	// ทุก Route ต้องผ่านการตรวจสิทธิ์ (RBAC) ตามบทบาทในฐานข้อมูล
+	adminApi := app.Group("/admin", jwtMiddleware, activeUser)
+	adminApi.Post("/book", requirePermission(models.PermBooksWrite), bookHandler.CreateBook)
+	adminApi.Put("/book/:id", requirePermission(models.PermBooksWrite), bookHandler.UpdateBook)
+	adminApi.Delete("/book/:id", requirePermission(models.PermBooksWrite), bookHandler.DeleteBook)
+	adminApi.Post("/inventory/movements", requirePermission(models.PermInventoryManage), inventoryHandler.CreateMovement)
+	adminApi.Get("/inventory/movements", requirePermission(models.PermInventoryManage), inventoryHandler.GetMovements)
+	adminApi.Get("/inventory/books/:id", requirePermission(models.PermInventoryManage), inventoryHandler.GetBookInventory)
+	adminApi.Post("/orders/:id/transition", requirePermission(models.PermOrdersManage), orderHandler.TransitionOrder)
+	adminApi.Post("/orders/:id/promptpay/confirm", requirePermission(models.PermPaymentsManage), paymentHandler.ConfirmPromptPayPayment)
+	adminApi.Get("/users", requirePermission(models.PermUsersManage), userHandler.GetUsers)
+	adminApi.Get("/users/:id", requirePermission(models.PermUsersManage), userHandler.GetUser)
+	adminApi.Put("/users/:id/role", requirePermission(models.PermUsersManage), userHandler.UpdateRole)
+	adminApi.Post("/users/:id/suspend", requirePermission(models.PermUsersManage), userHandler.SuspendUser)
+	adminApi.Post("/users/:id/unsuspend", requirePermission(models.PermUsersManage), userHandler.UnsuspendUser)
+	adminApi.Post("/users/:id/password-reset", requirePermission(models.PermUsersManage), userHandler.ForcePasswordReset)
+	adminApi.Post("/users/:id/revoke-sessions", requirePermission(models.PermUsersManage), userHandler.RevokeSessions)
+	adminApi.Get("/audit-logs", requirePermission(models.PermUsersManage), userHandler.GetAuditLogs)
+	adminApi.Get("/settings", requirePermission(models.PermSettingsManage), settingsHandler.GetSettings)
+	adminApi.Put("/settings/:key", requirePermission(models.PermSettingsManage), settingsHandler.UpdateSetting)
+
+	// กลุ่มผู้ใช้งานทั่วไป (User/API): จัดการตะกร้าสินค้าและคำสั่งซื้อ
+	userApi := app.Group("/api", jwtMiddleware, activeUser)
+	userApi.Post("/logout", authHandler.Logout)
+	userApi.Post("/logout-all", authHandler.LogoutAll)
+	userApi.Put("/me/language", authHandler.UpdateLanguage)
+	userApi.Post("/email/verify/resend", authHandler.ResendVerification)
+	userApi.Post("/cart", cartHandler.AddToCart)
+	userApi.Get("/cart", cartHandler.GetCart)
+	userApi.Put("/cart/:id", cartHandler.UpdateCartItem)
+	userApi.Delete("/cart/:id", cartHandler.DeleteCartItem)
+	// ผู้ใช้ที่ยังไม่ยืนยันอีเมลดูสินค้าได้ แต่สั่งซื้อไม่ได้ (ถ้าผู้ดูแลเปิดการตั้งค่าไว้)
+	userApi.Post("/checkout", middleware.RequireVerifiedEmail(store.Users(), store.Settings()), orderHandler.Checkout)
+	userApi.Get("/orders", orderHandler.GetOrders)
+	userApi.Get("/orders/:id", orderHandler.GetOrder)
+	userApi.Post("/orders/:id/pay", paymentHandler.PayOrder)
+	userApi.Get("/orders/:id/promptpay", paymentHandler.GetPromptPayQR)
+
+	return app
+}
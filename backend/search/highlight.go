@@ -0,0 +1,76 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+// ความยาวบริบท (จำนวนอักขระ) รอบคำที่พบ เมื่อตัดข้อความยาวเป็น Snippet
+const snippetContext = 60
+
+// ป้ายที่ใช้ครอบคำที่ตรงกับคำค้น
+const (
+	MarkOpen  = "<mark>"
+	MarkClose = "</mark>"
+)
+
+// Highlight: ครอบคำค้นที่พบในข้อความด้วย <mark> และตัดเหลือเฉพาะช่วงรอบคำแรกที่พบ
+// คืนค่าว่างถ้าไม่พบคำค้นใดเลย (ค้นแบบไม่สนตัวพิมพ์เล็ก/ใหญ่)
+// ข้อความเดิมถูก Escape เป็น HTML แล้ว จึงแสดงผลเป็น HTML ได้อย่างปลอดภัย
+func Highlight(text string, terms []Term) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// บางอักขระเปลี่ยนความยาวเมื่อแปลงเป็นตัวพิมพ์เล็ก ใช้ข้อความเดิมเทียบแทน
+		lower = runes
+	}
+
+	// 1. ทำเครื่องหมายตำแหน่งที่ตรงกับคำค้น
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, t := range terms {
+		needle := []rune(t.Text)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != t.Text {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+	if first == -1 {
+		return ""
+	}
+
+	// 2. ตัดเอาเฉพาะช่วงรอบคำแรกที่พบ
+	start, end := 0, len(runes)
+	if start < first-snippetContext {
+		start = first - snippetContext
+	}
+	if end > first+snippetContext*2 {
+		end = first + snippetContext*2
+	}
+
+	// 3. ประกอบข้อความพร้อมป้าย <mark>
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			b.WriteString(MarkOpen)
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			b.WriteString(MarkClose)
+		}
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+// Package search: เครื่องมือช่วยค้นหาหนังสือด้วย Full-text Search ของ PostgreSQL
+//
+// ภาษาไทยเขียนติดกันไม่มีช่องว่าง Parser ของ PostgreSQL จึงมองทั้งประโยคเป็นคำเดียว
+// เราจึงแยกอักษรไทยออกเป็นตัวละ Token (ทั้งตอนสร้าง tsvector และตอนค้นหา)
+// แล้วค้นด้วย Phrase Query (<->) ซึ่งให้ผลเทียบเท่าการค้นหาคำย่อยในข้อความ
+// โดยไม่ต้องพึ่งพจนานุกรมตัดคำ
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsThai: ตรวจสอบว่าเป็นอักขระไทยหรือไม่ (U+0E00–U+0E7F)
+// ช่วงนี้ต้องตรงกับ Regular Expression ของคอลัมน์ search_vector ใน Migration เสมอ
+func IsThai(r rune) bool {
+	return r >= 0x0E00 && r <= 0x0E7F
+}
+
+// SpaceThai: แทรกช่องว่างหลังอักขระไทยทุกตัว (แบบเดียวกับคอลัมน์ search_vector)
+func SpaceThai(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteRune(r)
+		if IsThai(r) {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// Term: คำค้นหนึ่งคำหลังจากทำความสะอาดแล้ว
+type Term struct {
+	Text string // ตัวพิมพ์เล็ก เหลือเฉพาะตัวอักษรและตัวเลข
+	Thai bool   // มีอักขระไทยอยู่ด้วย (ต้องค้นแบบ Phrase)
+}
+
+// Terms: แยกข้อความค้นหาเป็นคำ ตัดเครื่องหมายที่มีความหมายพิเศษใน tsquery ทิ้ง
+func Terms(q string) []Term {
+	var terms []Term
+	for _, field := range strings.Fields(strings.ToLower(q)) {
+		var b strings.Builder
+		thai := false
+		for _, r := range field {
+			switch {
+			case IsThai(r):
+				thai = true
+				b.WriteRune(r)
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			terms = append(terms, Term{Text: b.String(), Thai: thai})
+		}
+	}
+	return terms
+}
@@ -11,6 +11,24 @@ import sectionIcon from './assets/Icon.png'
 // ที่อยู่หลักของ API
 const API_BASE_URL = 'http://localhost:3000'
 
+// คำขอต่ออายุ Token ที่กำลังทำอยู่ (ใช้ร่วมกัน เพราะ Refresh Token ใช้ได้ครั้งเดียว
+// ถ้าส่งซ้ำพร้อมกันหลายคำขอ เซิร์ฟเวอร์จะถือว่าถูกขโมยและยกเลิกการเข้าสู่ระบบ)
+let refreshRequest = null
+
+const refreshAccessToken = () => {
+  if (!refreshRequest) {
+    refreshRequest = axios
+      .post(`${API_BASE_URL}/token/refresh`, { refresh_token: localStorage.getItem('refresh_token') })
+      .then(({ data }) => {
+        localStorage.setItem('token', data.token)
+        localStorage.setItem('refresh_token', data.refresh_token)
+        return data.token
+      })
+      .finally(() => { refreshRequest = null })
+  }
+  return refreshRequest
+}
+
 function App() {
   const navigate = useNavigate()
 
@@ -20,6 +38,7 @@ function App() {
   const [token, setToken] = useState(localStorage.getItem('token') || '')
   const [role, setRole] = useState(localStorage.getItem('role') || '')
   const [name, setName] = useState(localStorage.getItem('name') || '')
+  const [emailVerified] = useState(localStorage.getItem('email_verified') !== 'false')
 
   // ข้อมูลเกี่ยวกับหนังสือ
   const [books, setBooks] = useState([])
@@ -37,6 +56,31 @@ function App() {
 
   // --- 2. ผลกระทบย้อนกลับ (Side Effects) ---
 
+  // Access Token อายุสั้น: เมื่อได้ 401 ให้ต่ออายุด้วย Refresh Token แล้วส่งคำขอเดิมซ้ำ
+  useEffect(() => {
+    const interceptor = axios.interceptors.response.use(
+      (response) => response,
+      async (error) => {
+        const original = error.config
+        if (error.response?.status !== 401 || original._retried || !localStorage.getItem('refresh_token') ||
+          original.url.endsWith('/token/refresh')) {
+          return Promise.reject(error)
+        }
+        original._retried = true
+        try {
+          const newToken = await refreshAccessToken()
+          setToken(newToken)
+          original.headers.Authorization = `Bearer ${newToken}`
+          return axios(original)
+        } catch {
+          handleLogout()
+          return Promise.reject(error)
+        }
+      }
+    )
+    return () => axios.interceptors.response.eject(interceptor)
+  }, [])
+
   useEffect(() => {
     fetchBooks()
     if (token) {
@@ -49,8 +93,9 @@ function App() {
   // ดึงรายการหนังสือทั้งหมด
   const fetchBooks = async () => {
     try {
-      const response = await axios.get(`${API_BASE_URL}/books`)
-      setBooks(response.data)
+      // API ตอบกลับเป็นหน้า (data, total, next_cursor, ...) ดึงหน้าแรกขนาดสูงสุดมาแสดง
+      const response = await axios.get(`${API_BASE_URL}/books`, { params: { limit: 100 } })
+      setBooks(response.data.data)
     } catch (error) {
       console.error("โหลดหนังสือไม่สำเร็จ", error)
     }
@@ -71,7 +116,41 @@ function App() {
   // --- 4. การจัดการระบบสมาชิก ---
 
   // ออกจากระบบและล้างค่าข้อมูลทั้งหมด
+  // ส่งลิงก์ยืนยันอีเมลใหม่ (สำหรับผู้ใช้ที่ยังไม่ได้ยืนยัน)
+  const handleResendVerification = async () => {
+    try {
+      const response = await axios.post(`${API_BASE_URL}/api/email/verify/resend`, {}, {
+        headers: { Authorization: `Bearer ${token}` }
+      })
+      Swal.fire({
+        icon: 'info',
+        title: 'ตรวจสอบอีเมลของคุณ',
+        text: response.data.message,
+        background: '#1a1a2e',
+        color: '#fff',
+        confirmButtonColor: '#667eea'
+      })
+    } catch (error) {
+      Swal.fire({
+        icon: 'error',
+        title: 'ส่งอีเมลไม่สำเร็จ',
+        text: error.response?.data?.detail || 'ไม่สามารถเชื่อมต่อเซิร์ฟเวอร์ได้',
+        background: '#1a1a2e',
+        color: '#fff',
+        confirmButtonColor: '#ff416c'
+      })
+    }
+  }
+
   const handleLogout = () => {
+    // แจ้ง Server ให้เพิกถอนบัตรผ่าน (ถ้าล้มเหลวก็ออกจากระบบฝั่งเครื่องต่อไป)
+    const currentToken = localStorage.getItem('token')
+    if (currentToken) {
+      axios.post(`${API_BASE_URL}/api/logout`, {}, {
+        headers: { Authorization: `Bearer ${currentToken}` },
+        _retried: true,
+      }).catch(() => {})
+    }
     setToken('')
     setRole('')
     setName('')
@@ -165,10 +244,16 @@ function App() {
   // --- 6. การจัดการตะกร้าสินค้า ---
 
   // เปิดดูรายละเอียดหนังสือเพื่อเตรียมเพิ่มลงตะกร้า
-  const openBookDetail = (bookId) => {
-    const book = books.find(b => b.ID === bookId);
-    if (book) {
-      setSelectedBookForCart(book);
+  // ดึงข้อมูลล่าสุดจาก GET /books/:id (เบราว์เซอร์ใช้ ETag ตรวจซ้ำให้เอง) ถ้าไม่สำเร็จใช้ข้อมูลจากรายการแทน
+  const openBookDetail = async (bookId) => {
+    try {
+      const response = await axios.get(`${API_BASE_URL}/books/${bookId}`)
+      setSelectedBookForCart(response.data);
+    } catch (error) {
+      const book = books.find(b => b.ID === bookId);
+      if (book) {
+        setSelectedBookForCart(book);
+      }
     }
   };
 
@@ -218,7 +303,7 @@ function App() {
       Swal.fire({
         icon: 'error',
         title: 'เกิดข้อผิดพลาด',
-        text: error.response?.data?.error || 'ไม่สามารถเพิ่มสินค้าได้',
+        text: error.response?.data?.detail || 'ไม่สามารถเพิ่มสินค้าได้',
         background: '#1a1a2e', color: '#fff'
       });
     }
@@ -322,6 +407,14 @@ function App() {
         </div>
       </nav>
 
+      {/* แจ้งเตือนผู้ใช้ที่ยังไม่ยืนยันอีเมล (อาจสั่งซื้อไม่ได้จนกว่าจะยืนยัน) */}
+      {token && !emailVerified && (
+        <div className="glass-panel" style={{ margin: '10px auto', maxWidth: '900px', padding: '10px 20px', display: 'flex', justifyContent: 'space-between', alignItems: 'center', color: '#fcd34d' }}>
+          <span>📧 กรุณายืนยันอีเมลของคุณจากลิงก์ที่ส่งไปให้</span>
+          <button className="btn-secondary" onClick={handleResendVerification}>ส่งลิงก์อีกครั้ง</button>
+        </div>
+      )}
+
       {/* หน้าต่างตะกร้าสินค้า */}
       {showCart && (
         <Cart
@@ -368,6 +461,9 @@ function App() {
                 placeholder="จำนวนสินค้า (Stock)..."
                 value={newBook.stock}
                 onChange={e => setNewBook({ ...newBook, stock: parseInt(e.target.value) || 0 })}
+                // สต็อกของหนังสือที่มีอยู่แล้วแก้ผ่าน /admin/inventory เท่านั้น
+                disabled={isEditing}
+                title={isEditing ? 'แก้สต็อกผ่านการบันทึกการเคลื่อนไหวของสต็อก' : undefined}
                 required
               />
               <input className="glass-input" placeholder="ลิงก์รูปภาพ (URL)..." value={newBook.image_url} onChange={e => setNewBook({ ...newBook, image_url: e.target.value })} />
@@ -0,0 +1,94 @@
+import { useState } from 'react'
+import { Link } from 'react-router-dom'
+import axios from 'axios'
+import Swal from 'sweetalert2'
+import './Login.css'
+
+// ที่อยู่หลักของ API
+const API_BASE_URL = 'http://localhost:3000'
+
+// หน้าลืมรหัสผ่าน: ขอลิงก์ตั้งรหัสผ่านใหม่ทางอีเมล
+function ForgotPassword() {
+    const [email, setEmail] = useState('')
+    const [isLoading, setIsLoading] = useState(false)
+
+    const handleSubmit = async (e) => {
+        e.preventDefault()
+        setIsLoading(true)
+
+        try {
+            // Server ตอบเหมือนกันทุกกรณี ไม่บอกว่าอีเมลนี้มีในระบบหรือไม่
+            const response = await axios.post(`${API_BASE_URL}/password/forgot`, { email })
+            Swal.fire({
+                icon: 'info',
+                title: 'ตรวจสอบอีเมลของคุณ',
+                text: response.data.message,
+                background: '#1a1a2e',
+                color: '#fff',
+                confirmButtonColor: '#667eea'
+            })
+        } catch (error) {
+            console.error("Forgot Password Error:", error)
+            Swal.fire({
+                icon: 'error',
+                title: 'ส่งคำขอไม่สำเร็จ',
+                text: error.response?.data?.detail || 'ไม่สามารถเชื่อมต่อเซิร์ฟเวอร์ได้ กรุณาลองใหม่ภายหลัง',
+                background: '#1a1a2e',
+                color: '#fff',
+                confirmButtonColor: '#ff416c'
+            })
+        } finally {
+            setIsLoading(false)
+        }
+    }
+
+    return (
+        <div className="login-page">
+            <div className="login-space-background"></div>
+            <div className="login-stars"></div>
+
+            <nav className="login-navbar">
+                <Link to="/" className="login-navbar-logo">
+                    <span>🚀</span>
+                    SPACE BOOK STORE
+                </Link>
+            </nav>
+
+            <div className="login-container">
+                <div className="login-glass-card">
+                    <div className="login-page-header">
+                        <div className="login-page-icon">🔑</div>
+                        <h1 className="login-page-title">Forgot Password</h1>
+                        <p className="login-page-subtitle">กรอกอีเมลเพื่อรับลิงก์ตั้งรหัสผ่านใหม่</p>
+                    </div>
+
+                    <form className="login-page-form" onSubmit={handleSubmit}>
+                        <div className="login-page-input-group">
+                            <label htmlFor="email">Email Address</label>
+                            <input
+                                id="email"
+                                type="email"
+                                className="login-page-input"
+                                placeholder="your@email.com"
+                                value={email}
+                                onChange={(e) => setEmail(e.target.value)}
+                                required
+                                autoFocus
+                            />
+                        </div>
+
+                        <button type="submit" className="btn-login-page-submit" disabled={isLoading}>
+                            {isLoading ? '⏳ กำลังส่ง...' : '📧 ส่งลิงก์ตั้งรหัสผ่านใหม่'}
+                        </button>
+                    </form>
+
+                    <div className="login-back-link">
+                        <Link to="/login">กลับไปหน้าเข้าสู่ระบบ</Link>
+                    </div>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default ForgotPassword
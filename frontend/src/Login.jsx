@@ -24,12 +24,15 @@ function Login({ onLoginSuccess }) {
             })
 
             // 2. Destructure ข้อมูลจาก response.data ให้สะอาดตา
-            const { token, role, name } = response.data
+            const { token, refresh_token, role, name, email_verified } = response.data
 
             // บันทึกข้อมูลลง LocalStorage ตามสถาปัตยกรรมเดิม
+            // (refresh_token ใช้ขอ token ใหม่เมื่อหมดอายุ ดู App.jsx)
             localStorage.setItem('token', token)
+            localStorage.setItem('refresh_token', refresh_token)
             localStorage.setItem('role', role)
             localStorage.setItem('name', name)
+            localStorage.setItem('email_verified', String(email_verified))
 
             // แจ้งสถานะกลับไปยัง Component หลักถ้ามีการระบุมา
             if (onLoginSuccess) {
@@ -153,6 +156,10 @@ function Login({ onLoginSuccess }) {
                             ยังไม่มีบัญชี? สมัครสมาชิกใหม่
                         </Link>
 
+                        <Link to="/forgot-password">
+                            ลืมรหัสผ่าน?
+                        </Link>
+
                         <Link to="/">
                             <svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="none" stroke="currentColor" strokeWidth="2" strokeLinecap="round" strokeLinejoin="round">
                                 <line x1="19" y1="12" x2="5" y2="12"></line>
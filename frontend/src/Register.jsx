@@ -101,7 +101,12 @@ function Register() {
 
         } catch (error) {
             // 4. ถ้ามี Error (เช่น อีเมลซ้ำ หรือเซิร์ฟเวอร์มีปัญหา)
-            let errorMessage = error.response?.data?.error || 'เกิดข้อผิดพลาดบางอย่างในการสมัครสมาชิก'
+            let errorMessage = error.response?.data?.detail || 'เกิดข้อผิดพลาดบางอย่างในการสมัครสมาชิก'
+            // 422: แสดงรายการ Field ที่ไม่ผ่านการตรวจสอบ
+            const fields = error.response?.data?.fields
+            if (fields?.length) {
+                errorMessage = fields.map((f) => `${f.field}: ${f.message}`).join('\n')
+            }
             if (!error.response) {
                 errorMessage = 'ไม่สามารถเชื่อมต่อเครื่องแม่ข่ายได้ กรุณาลองใหม่ภายหลัง'
             }
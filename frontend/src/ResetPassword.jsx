@@ -0,0 +1,126 @@
+import { useState } from 'react'
+import { useNavigate, useSearchParams, Link } from 'react-router-dom'
+import axios from 'axios'
+import Swal from 'sweetalert2'
+import './Login.css'
+
+// ที่อยู่หลักของ API
+const API_BASE_URL = 'http://localhost:3000'
+
+// หน้าตั้งรหัสผ่านใหม่: เปิดจากลิงก์ในอีเมล (/reset-password?token=...)
+function ResetPassword() {
+    const navigate = useNavigate()
+    const [searchParams] = useSearchParams()
+    const token = searchParams.get('token') || ''
+    const [password, setPassword] = useState('')
+    const [confirmPassword, setConfirmPassword] = useState('')
+    const [isLoading, setIsLoading] = useState(false)
+
+    const handleSubmit = async (e) => {
+        e.preventDefault()
+        if (password !== confirmPassword) {
+            Swal.fire({
+                icon: 'warning',
+                title: 'รหัสผ่านไม่ตรงกัน',
+                background: '#1a1a2e',
+                color: '#fff',
+                confirmButtonColor: '#ff416c'
+            })
+            return
+        }
+        setIsLoading(true)
+
+        try {
+            await axios.post(`${API_BASE_URL}/password/reset`, { token, password })
+            // ทุก Session เดิมถูกยกเลิกแล้ว ล้างข้อมูลในเครื่องและให้เข้าสู่ระบบใหม่
+            localStorage.clear()
+            Swal.fire({
+                icon: 'success',
+                title: 'ตั้งรหัสผ่านใหม่สำเร็จ',
+                text: 'กรุณาเข้าสู่ระบบด้วยรหัสผ่านใหม่',
+                background: '#1a1a2e',
+                color: '#fff',
+                confirmButtonColor: '#667eea'
+            }).then(() => {
+                navigate('/login')
+            })
+        } catch (error) {
+            console.error("Reset Password Error:", error)
+            Swal.fire({
+                icon: 'error',
+                title: 'ตั้งรหัสผ่านใหม่ไม่สำเร็จ',
+                text: error.response?.data?.detail || 'ไม่สามารถเชื่อมต่อเซิร์ฟเวอร์ได้ กรุณาลองใหม่ภายหลัง',
+                background: '#1a1a2e',
+                color: '#fff',
+                confirmButtonColor: '#ff416c'
+            })
+        } finally {
+            setIsLoading(false)
+        }
+    }
+
+    return (
+        <div className="login-page">
+            <div className="login-space-background"></div>
+            <div className="login-stars"></div>
+
+            <nav className="login-navbar">
+                <Link to="/" className="login-navbar-logo">
+                    <span>🚀</span>
+                    SPACE BOOK STORE
+                </Link>
+            </nav>
+
+            <div className="login-container">
+                <div className="login-glass-card">
+                    <div className="login-page-header">
+                        <div className="login-page-icon">🔐</div>
+                        <h1 className="login-page-title">Reset Password</h1>
+                        <p className="login-page-subtitle">ตั้งรหัสผ่านใหม่ (อย่างน้อย 8 ตัวอักษร)</p>
+                    </div>
+
+                    <form className="login-page-form" onSubmit={handleSubmit}>
+                        <div className="login-page-input-group">
+                            <label htmlFor="password">New Password</label>
+                            <input
+                                id="password"
+                                type="password"
+                                className="login-page-input"
+                                placeholder="••••••••"
+                                value={password}
+                                onChange={(e) => setPassword(e.target.value)}
+                                minLength={8}
+                                required
+                                autoFocus
+                            />
+                        </div>
+
+                        <div className="login-page-input-group">
+                            <label htmlFor="confirmPassword">Confirm Password</label>
+                            <input
+                                id="confirmPassword"
+                                type="password"
+                                className="login-page-input"
+                                placeholder="••••••••"
+                                value={confirmPassword}
+                                onChange={(e) => setConfirmPassword(e.target.value)}
+                                minLength={8}
+                                required
+                            />
+                        </div>
+
+                        <button type="submit" className="btn-login-page-submit" disabled={isLoading || !token}>
+                            {isLoading ? '⏳ กำลังบันทึก...' : '🔐 ตั้งรหัสผ่านใหม่'}
+                        </button>
+                    </form>
+
+                    <div className="login-back-link">
+                        <Link to="/login">กลับไปหน้าเข้าสู่ระบบ</Link>
+                    </div>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default ResetPassword
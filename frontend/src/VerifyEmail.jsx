@@ -0,0 +1,70 @@
+import { useEffect, useRef, useState } from 'react'
+import { useSearchParams, Link } from 'react-router-dom'
+import axios from 'axios'
+import './Login.css'
+
+// ที่อยู่หลักของ API
+const API_BASE_URL = 'http://localhost:3000'
+
+// หน้ายืนยันอีเมล: เปิดจากลิงก์ในอีเมล (/verify-email?token=...) แล้วยืนยันทันที
+function VerifyEmail() {
+    const [searchParams] = useSearchParams()
+    const token = searchParams.get('token') || ''
+    const [status, setStatus] = useState('loading') // loading | success | error
+    const [message, setMessage] = useState('')
+    const requested = useRef(false) // กันการเรียกซ้ำใน StrictMode
+
+    useEffect(() => {
+        if (requested.current) return
+        requested.current = true
+
+        axios.post(`${API_BASE_URL}/email/verify`, { token })
+            .then(() => {
+                // ถ้าผู้ใช้เข้าสู่ระบบค้างไว้ในเครื่องนี้ ให้เลิกแสดงแจ้งเตือนยืนยันอีเมล
+                if (localStorage.getItem('email_verified') !== null) {
+                    localStorage.setItem('email_verified', 'true')
+                }
+                setStatus('success')
+            })
+            .catch((error) => {
+                setMessage(error.response?.data?.detail || 'ไม่สามารถเชื่อมต่อเซิร์ฟเวอร์ได้ กรุณาลองใหม่ภายหลัง')
+                setStatus('error')
+            })
+    }, [token])
+
+    return (
+        <div className="login-page">
+            <div className="login-space-background"></div>
+            <div className="login-stars"></div>
+
+            <nav className="login-navbar">
+                <Link to="/" className="login-navbar-logo">
+                    <span>🚀</span>
+                    SPACE BOOK STORE
+                </Link>
+            </nav>
+
+            <div className="login-container">
+                <div className="login-glass-card">
+                    <div className="login-page-header">
+                        <div className="login-page-icon">
+                            {status === 'loading' ? '⏳' : status === 'success' ? '✅' : '⚠️'}
+                        </div>
+                        <h1 className="login-page-title">Verify Email</h1>
+                        <p className="login-page-subtitle">
+                            {status === 'loading' && 'กำลังยืนยันอีเมล...'}
+                            {status === 'success' && 'ยืนยันอีเมลสำเร็จ ขอบคุณครับ'}
+                            {status === 'error' && message}
+                        </p>
+                    </div>
+
+                    <div className="login-back-link">
+                        <Link to="/">กลับไปยังหน้าแรก</Link>
+                    </div>
+                </div>
+            </div>
+        </div>
+    )
+}
+
+export default VerifyEmail
@@ -5,6 +5,9 @@ import './index.css'
 import App from './App.jsx'
 import Login from './Login.jsx'
 import Register from './Register.jsx'
+import ForgotPassword from './ForgotPassword.jsx'
+import ResetPassword from './ResetPassword.jsx'
+import VerifyEmail from './VerifyEmail.jsx'
 
 // Component สำหรับหน้า 404: หลงทางในอวกาศ
 const NotFound = () => (
@@ -43,6 +46,9 @@ createRoot(document.getElementById('root')).render(
         <Route path="/" element={<App />} />
         <Route path="/login" element={<Login />} />
         <Route path="/register" element={<Register />} />
+        <Route path="/forgot-password" element={<ForgotPassword />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
+        <Route path="/verify-email" element={<VerifyEmail />} />
 
         {/* จัดการหน้าที่ไม่มีในระบบ (404 Not Found) */}
         <Route path="*" element={<NotFound />} />